@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/AjayShukla007/logsentinel/internal/config"
+)
+
+const configUsage = `usage: server config check [flags]
+
+commands:
+  check   validate the configuration and print it with secrets masked`
+
+// runConfig runs the config subcommand and returns the exit code.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Println(configUsage)
+		return 2
+	}
+
+	cfg, rest, err := config.Load("config check", args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 1
+	}
+	if len(rest) > 0 {
+		fmt.Println(configUsage)
+		return 2
+	}
+
+	fmt.Print(cfg)
+	fmt.Println("# configuration is valid")
+	return 0
+}
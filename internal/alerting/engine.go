@@ -0,0 +1,319 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/reload"
+	"github.com/AjayShukla007/logsentinel/internal/repository/alert"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+const (
+	evaluationJobName = "alert_evaluation"
+	// queueSize bounds the logs waiting for pattern matching, ingestion
+	// never blocks on the engine and drops observations once it's full
+	queueSize = 4096
+)
+
+// Event is an ingested log as seen by the engine.
+type Event struct {
+	ProjectID uuid.UUID
+	Category  string
+	Message   string
+	CreatedAt time.Time
+}
+
+type compiledRule struct {
+	rule    *alert.Rule
+	pattern *regexp.Regexp
+}
+
+// Engine evaluates alert rules. Pattern rules are matched against the
+// ingestion stream as logs arrive, threshold rules are evaluated by a
+// scheduled job counting logs over each rule's window. Firing and resolving
+// go through the repository so only the first firing of an open alert is
+// reported no matter how many replicas observe it.
+type Engine struct {
+	db       *pgxpool.Pool
+	repo     alert.Repository
+	notifier *notify.Dispatcher
+
+	mu    sync.RWMutex
+	rules map[uuid.UUID][]compiledRule
+
+	events   chan Event
+	reloads  *reload.Loop
+	quit     chan struct{}
+	stopOnce sync.Once
+	matching sync.WaitGroup
+}
+
+func NewEngine(db *pgxpool.Pool, repo alert.Repository, notifier *notify.Dispatcher) *Engine {
+	e := &Engine{
+		db:       db,
+		repo:     repo,
+		notifier: notifier,
+		rules:    make(map[uuid.UUID][]compiledRule),
+		events:   make(chan Event, queueSize),
+		quit:     make(chan struct{}),
+	}
+	e.reloads = reload.New("alert rules", e.Reload)
+	return e
+}
+
+// RegisterJobs adds the windowed evaluation job.
+func (e *Engine) RegisterJobs(sched *scheduler.Scheduler) error {
+	return sched.Register(scheduler.Job{
+		Name:     evaluationJobName,
+		Schedule: "* * * * *",
+		Timeout:  50 * time.Second,
+		Run:      e.evaluate,
+	})
+}
+
+// Start loads the rules, starts matching observed logs and reloads the rules
+// periodically until Stop is called. Reloads run on their own goroutine so
+// matching never waits for the database.
+func (e *Engine) Start(ctx context.Context) error {
+	if err := e.reloads.Start(ctx); err != nil {
+		return err
+	}
+	e.matching.Add(1)
+	go e.run()
+	return nil
+}
+
+// Stop matches the logs still queued and stops the engine. The queue is
+// matched even when a reload didn't finish in time.
+func (e *Engine) Stop(ctx context.Context) error {
+	err := e.reloads.Stop(ctx)
+
+	e.stopOnce.Do(func() { close(e.quit) })
+	done := make(chan struct{})
+	go func() {
+		e.matching.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reload replaces the cached rules with the enabled rules in the database.
+func (e *Engine) Reload(ctx context.Context) error {
+	rules, err := e.repo.ListEnabledRules(ctx)
+	if err != nil {
+		return fmt.Errorf("loading alert rules: %w", err)
+	}
+
+	byProject := make(map[uuid.UUID][]compiledRule)
+	for _, rule := range rules {
+		compiled := compiledRule{rule: rule}
+		if rule.Kind == alert.KindPattern {
+			compiled.pattern, err = regexp.Compile(rule.Pattern)
+			if err != nil {
+				slog.Warn("Skipping alert rule with invalid pattern", "rule", rule.ID, "err", err)
+				continue
+			}
+		}
+		byProject[rule.ProjectID] = append(byProject[rule.ProjectID], compiled)
+	}
+
+	e.mu.Lock()
+	e.rules = byProject
+	e.mu.Unlock()
+	return nil
+}
+
+// Observe queues an ingested log for pattern matching without blocking.
+func (e *Engine) Observe(event Event) {
+	if !e.hasPatternRules(event.ProjectID) {
+		return
+	}
+
+	select {
+	case e.events <- event:
+	default:
+		slog.Warn("Alert queue full, skipping log", "project_id", event.ProjectID)
+	}
+}
+
+// Backlog returns how many logs wait for pattern matching and how many the
+// queue holds.
+func (e *Engine) Backlog() (int, int) {
+	return len(e.events), cap(e.events)
+}
+
+func (e *Engine) hasPatternRules(projectID uuid.UUID) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, compiled := range e.rules[projectID] {
+		if compiled.pattern != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) run() {
+	defer e.matching.Done()
+
+	for {
+		select {
+		case event := <-e.events:
+			e.match(event)
+		case <-e.quit:
+			for {
+				select {
+				case event := <-e.events:
+					e.match(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *Engine) match(event Event) {
+	e.mu.RLock()
+	rules := e.rules[event.ProjectID]
+	e.mu.RUnlock()
+
+	for _, compiled := range rules {
+		if compiled.pattern == nil || !categoryMatches(compiled.rule, event.Category) {
+			continue
+		}
+		if !compiled.pattern.MatchString(event.Message) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		e.fire(ctx, compiled.rule, 1, event.Message)
+		cancel()
+	}
+}
+
+// evaluate runs the windowed checks: threshold rules fire or resolve on the
+// number of logs in their window, pattern rules resolve once their window
+// passed without a match.
+func (e *Engine) evaluate(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+
+	// evaluation runs on the replica holding the job lock, reload so it
+	// doesn't wait for its ticker to see rules changed through other replicas
+	if err := e.Reload(ctx); err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	var rules []*alert.Rule
+	for _, compiled := range e.rules {
+		for _, c := range compiled {
+			rules = append(rules, c.rule)
+		}
+	}
+	e.mu.RUnlock()
+
+	for _, rule := range rules {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch rule.Kind {
+		case alert.KindThreshold:
+			count, err := e.countLogs(ctx, rule)
+			if err != nil {
+				slog.Error("Error evaluating alert rule", "rule", rule.ID, "err", err)
+				continue
+			}
+			if count > rule.Threshold {
+				e.fire(ctx, rule, count, fmt.Sprintf("%d logs in the last %s, threshold is %d", count, rule.Window, rule.Threshold))
+			} else {
+				e.resolve(ctx, rule)
+			}
+
+		case alert.KindPattern:
+			open, err := e.repo.GetOpenAlert(ctx, rule.ID)
+			if err != nil {
+				slog.Error("Error evaluating alert rule", "rule", rule.ID, "err", err)
+				continue
+			}
+			if open != nil && time.Since(open.LastSeenAt) > rule.Window {
+				e.resolve(ctx, rule)
+			}
+		}
+		run.RowsAffected++
+	}
+
+	return nil
+}
+
+func (e *Engine) countLogs(ctx context.Context, rule *alert.Rule) (int64, error) {
+	var count int64
+	err := e.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM logs
+		WHERE project_id = $1
+			AND ($2 = '' OR category = $2)
+			AND created_at >= CURRENT_TIMESTAMP - make_interval(secs => $3)`,
+		rule.ProjectID, rule.Category, rule.Window.Seconds(),
+	).Scan(&count)
+	return count, err
+}
+
+func (e *Engine) fire(ctx context.Context, rule *alert.Rule, value int64, message string) {
+	a, opened, err := e.repo.Fire(ctx, rule, value, message)
+	if err != nil {
+		slog.Error("Error firing alert rule", "rule", rule.ID, "err", err)
+		return
+	}
+	if opened {
+		slog.Info("Alert firing", "rule", rule.Name, "project_id", a.ProjectID, "value", a.Value)
+		e.notifier.Notify(alertEvent(notify.KindAlertFiring, notify.SeverityCritical, rule, a))
+	}
+}
+
+func (e *Engine) resolve(ctx context.Context, rule *alert.Rule) {
+	a, err := e.repo.Resolve(ctx, rule.ID)
+	if err != nil {
+		slog.Error("Error resolving alert rule", "rule", rule.ID, "err", err)
+		return
+	}
+	if a != nil {
+		slog.Info("Alert resolved", "rule", rule.Name, "project_id", a.ProjectID)
+		e.notifier.Notify(alertEvent(notify.KindAlertResolved, notify.SeverityInfo, rule, a))
+	}
+}
+
+func alertEvent(kind, severity string, rule *alert.Rule, a *alert.Alert) notify.Event {
+	return notify.Event{
+		Kind:      kind,
+		Severity:  severity,
+		Title:     rule.Name,
+		ProjectID: a.ProjectID.String(),
+		Fields: map[string]string{
+			"rule_id":  rule.ID.String(),
+			"alert_id": a.ID.String(),
+			"value":    fmt.Sprint(a.Value),
+			"message":  a.Message,
+		},
+	}
+}
+
+func categoryMatches(rule *alert.Rule, category string) bool {
+	return rule.Category == "" || rule.Category == category
+}
@@ -0,0 +1,225 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/repository/anomaly"
+	"github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+const detectionJobName = "anomaly_detection"
+
+const week = 7 * 24 * time.Hour
+
+type Config struct {
+	// Weeks is how many weeks of the same hour of week form the baseline,
+	// hour rollups must be kept at least that long
+	Weeks int
+	// MinWeeks is how many of those weeks the project must have logged in
+	// before its hours are scored
+	MinWeeks int
+	// Threshold is the score from which an hour is an anomaly
+	Threshold float64
+	// MinCount ignores hours where both the observed count and the baseline
+	// are below it, small counts swing too much to be meaningful
+	MinCount int64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Weeks:     4,
+		MinWeeks:  2,
+		Threshold: 4,
+		MinCount:  20,
+	}
+}
+
+type seriesKey struct {
+	projectID uuid.UUID
+	category  string
+}
+
+// Detector flags hours whose log volume deviates from the seasonal baseline
+// of the project and category. The baseline is the median of the same hour
+// of the previous weeks, so daily and weekly cycles need no tuning. Counts
+// come from the rollups, which keep the hourly volume beyond log retention.
+type Detector struct {
+	stats    logstats.Repository
+	repo     anomaly.Repository
+	notifier *notify.Dispatcher
+	config   Config
+}
+
+func NewDetector(stats logstats.Repository, repo anomaly.Repository, notifier *notify.Dispatcher, config Config) *Detector {
+	return &Detector{
+		stats:    stats,
+		repo:     repo,
+		notifier: notifier,
+		config:   config,
+	}
+}
+
+// RegisterJobs adds the hourly detection job, it scores the previous hour
+// once its rollups are flushed.
+func (d *Detector) RegisterJobs(sched *scheduler.Scheduler) error {
+	return sched.Register(scheduler.Job{
+		Name:     detectionJobName,
+		Schedule: "5 * * * *",
+		Timeout:  10 * time.Minute,
+		Run:      d.detect,
+	})
+}
+
+func (d *Detector) detect(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+
+	hours := make([]time.Time, 0, d.config.Weeks+1)
+	for i := 0; i <= d.config.Weeks; i++ {
+		hours = append(hours, hour.Add(-time.Duration(i)*week))
+	}
+
+	counts, err := d.stats.GetHourlyCounts(ctx, hours)
+	if err != nil {
+		return fmt.Errorf("loading hourly counts: %w", err)
+	}
+
+	// series holds the count of every hour, index 0 is the scored hour
+	series := make(map[seriesKey][]int64)
+	active := make(map[uuid.UUID][]bool)
+	for _, c := range counts {
+		i := int(hour.Sub(c.Hour) / week)
+		key := seriesKey{projectID: c.ProjectID, category: c.Category}
+		if series[key] == nil {
+			series[key] = make([]int64, len(hours))
+		}
+		series[key][i] = c.Logs
+
+		if active[c.ProjectID] == nil {
+			active[c.ProjectID] = make([]bool, len(hours))
+		}
+		active[c.ProjectID][i] = active[c.ProjectID][i] || c.Logs > 0
+	}
+
+	for key, values := range series {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		weeks := 0
+		for _, logged := range active[key.projectID][1:] {
+			if logged {
+				weeks++
+			}
+		}
+		if weeks < d.config.MinWeeks {
+			continue
+		}
+
+		a := d.score(values[0], values[1:])
+		if a == nil {
+			continue
+		}
+		a.ID = uuid.New()
+		a.ProjectID = key.projectID
+		a.Category = key.category
+		a.Hour = hour
+
+		created, err := d.repo.Save(ctx, a)
+		if err != nil {
+			slog.Error("Error saving anomaly", "project_id", key.projectID, "err", err)
+			continue
+		}
+		if created {
+			run.RowsAffected++
+			slog.Info("Anomaly detected", "project_id", a.ProjectID, "category", a.Category,
+				"observed", a.Observed, "baseline", a.Baseline, "score", a.Score)
+			d.notifier.Notify(d.event(a))
+		}
+	}
+
+	return nil
+}
+
+// score compares the observed count to the median of the history. The
+// deviation is scaled by the median absolute deviation, floored at the
+// Poisson noise of the median so flat histories don't flag every change.
+func (d *Detector) score(observed int64, history []int64) *anomaly.Anomaly {
+	values := make([]float64, len(history))
+	for i, v := range history {
+		values[i] = float64(v)
+	}
+	median := medianOf(values)
+	if float64(observed) < float64(d.config.MinCount) && median < float64(d.config.MinCount) {
+		return nil
+	}
+
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	scale := math.Max(1.4826*medianOf(deviations), math.Sqrt(math.Max(median, 1)))
+
+	score := (float64(observed) - median) / scale
+	if math.Abs(score) < d.config.Threshold {
+		return nil
+	}
+
+	direction := anomaly.DirectionSpike
+	if score < 0 {
+		direction = anomaly.DirectionDrop
+	}
+	return &anomaly.Anomaly{
+		Observed:  observed,
+		Baseline:  median,
+		Score:     math.Round(score*100) / 100,
+		Direction: direction,
+	}
+}
+
+func (d *Detector) event(a *anomaly.Anomaly) notify.Event {
+	severity := notify.SeverityWarning
+	if math.Abs(a.Score) >= 2*d.config.Threshold {
+		severity = notify.SeverityCritical
+	}
+
+	return notify.Event{
+		Kind:      notify.KindAnomaly,
+		Severity:  severity,
+		Title:     fmt.Sprintf("Unusual %s log volume (%s)", a.Category, a.Direction),
+		ProjectID: a.ProjectID.String(),
+		Fields: map[string]string{
+			"anomaly_id": a.ID.String(),
+			"category":   a.Category,
+			"hour":       a.Hour.Format(time.RFC3339),
+			"observed":   fmt.Sprint(a.Observed),
+			"baseline":   fmt.Sprintf("%.1f", a.Baseline),
+			"score":      fmt.Sprintf("%.2f", a.Score),
+		},
+		Time: a.DetectedAt,
+	}
+}
+
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
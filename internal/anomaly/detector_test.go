@@ -0,0 +1,99 @@
+package anomaly
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/anomaly"
+)
+
+func TestScore(t *testing.T) {
+	flat := []int64{100, 100, 100, 100}
+	noisy := []int64{80, 100, 120, 100}
+
+	tests := []struct {
+		name     string
+		observed int64
+		history  []int64
+		// want is nil when the hour isn't an anomaly
+		want *anomaly.Anomaly
+	}{
+		{"matches baseline", 100, flat, nil},
+		{"within poisson noise", 130, flat, nil},
+		{
+			name:     "at threshold",
+			observed: 140,
+			history:  flat,
+			want:     &anomaly.Anomaly{Observed: 140, Baseline: 100, Score: 4, Direction: anomaly.DirectionSpike},
+		},
+		{
+			name:     "spike",
+			observed: 200,
+			history:  flat,
+			want:     &anomaly.Anomaly{Observed: 200, Baseline: 100, Score: 10, Direction: anomaly.DirectionSpike},
+		},
+		{
+			name:     "drop",
+			observed: 0,
+			history:  flat,
+			want:     &anomaly.Anomaly{Observed: 0, Baseline: 100, Score: -10, Direction: anomaly.DirectionDrop},
+		},
+		{"noisy history absorbs change", 150, noisy, nil},
+		{
+			name:     "noisy history scaled by deviation",
+			observed: 160,
+			history:  noisy,
+			want:     &anomaly.Anomaly{Observed: 160, Baseline: 100, Score: 4.05, Direction: anomaly.DirectionSpike},
+		},
+		{"small counts ignored", 15, []int64{5, 5, 5, 5}, nil},
+		{
+			name:     "large count over small baseline",
+			observed: 60,
+			history:  []int64{5, 5, 5, 5},
+			want:     &anomaly.Anomaly{Observed: 60, Baseline: 5, Score: 24.6, Direction: anomaly.DirectionSpike},
+		},
+		{"no history small count", 10, nil, nil},
+		{
+			name:     "no history",
+			observed: 50,
+			history:  nil,
+			want:     &anomaly.Anomaly{Observed: 50, Baseline: 0, Score: 50, Direction: anomaly.DirectionSpike},
+		},
+	}
+
+	d := &Detector{config: DefaultConfig()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.score(tt.observed, tt.history)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("score(%d, %v) = %+v, want %+v", tt.observed, tt.history, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedianOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{3}, 3},
+		{"odd count", []float64{3, 1, 2}, 2},
+		{"even count", []float64{4, 1, 3, 2}, 2.5},
+		{"duplicates", []float64{7, 7, 1, 7}, 7},
+		{"negative", []float64{-2, -8, 4}, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]float64(nil), tt.values...)
+			if got := medianOf(input); got != tt.want {
+				t.Errorf("medianOf(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+			if !reflect.DeepEqual(input, tt.values) {
+				t.Errorf("medianOf reordered its input to %v", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const manifestName = "manifest.json"
+
+// Manifest lists the archive files of one project and day.
+type Manifest struct {
+	ProjectID string         `json:"project_id"`
+	Date      string         `json:"date"`
+	Files     []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Key          string    `json:"key"`
+	Format       string    `json:"format"`
+	Rows         int       `json:"rows"`
+	Bytes        int       `json:"bytes"`
+	SHA256       string    `json:"sha256"`
+	MinCreatedAt time.Time `json:"min_created_at"`
+	MaxCreatedAt time.Time `json:"max_created_at"`
+	ArchivedAt   time.Time `json:"archived_at"`
+}
+
+// Archiver writes expired logs to the store, laid out as
+// <prefix>/<project_id>/<yyyy>/<mm>/<dd>/ with a manifest per directory.
+// Files are written before the manifest references them, so a crash can
+// leave an unreferenced file behind but never a manifest entry without data.
+type Archiver struct {
+	store  Store
+	format string
+	prefix string
+}
+
+func NewArchiver(store Store, format, prefix string) (*Archiver, error) {
+	if format != FormatNDJSON && format != FormatParquet {
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+	return &Archiver{store: store, format: format, prefix: prefix}, nil
+}
+
+// Archive stores records of a single project, one file per day they span.
+func (a *Archiver) Archive(ctx context.Context, projectID uuid.UUID, records []Record) error {
+	byDay := make(map[string][]Record)
+	for _, record := range records {
+		day := record.CreatedAt.UTC().Format("2006-01-02")
+		byDay[day] = append(byDay[day], record)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		if err := a.archiveDay(ctx, projectID, day, byDay[day]); err != nil {
+			return fmt.Errorf("archiving %s: %w", day, err)
+		}
+	}
+	return nil
+}
+
+func (a *Archiver) archiveDay(ctx context.Context, projectID uuid.UUID, day string, records []Record) error {
+	data, err := Encode(a.format, records)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	dir := a.dayDir(projectID, day)
+	key := path.Join(dir, fmt.Sprintf("%s-%s%s", time.Now().UTC().Format("20060102T150405"), uuid.New().String()[:8], extension(a.format)))
+
+	if err := a.store.Put(ctx, key, data); err != nil {
+		return err
+	}
+
+	file := ManifestFile{
+		Key:          key,
+		Format:       a.format,
+		Rows:         len(records),
+		Bytes:        len(data),
+		SHA256:       hex.EncodeToString(sum[:]),
+		MinCreatedAt: records[0].CreatedAt,
+		MaxCreatedAt: records[0].CreatedAt,
+		ArchivedAt:   time.Now().UTC(),
+	}
+	for _, record := range records[1:] {
+		if record.CreatedAt.Before(file.MinCreatedAt) {
+			file.MinCreatedAt = record.CreatedAt
+		}
+		if record.CreatedAt.After(file.MaxCreatedAt) {
+			file.MaxCreatedAt = record.CreatedAt
+		}
+	}
+
+	manifest, err := a.GetManifest(ctx, projectID, day)
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, file)
+
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.store.Put(ctx, path.Join(dir, manifestName), encoded)
+}
+
+// GetManifest loads the manifest of a project and day, it returns an empty
+// manifest when nothing was archived for that day yet.
+func (a *Archiver) GetManifest(ctx context.Context, projectID uuid.UUID, day string) (*Manifest, error) {
+	data, err := a.store.Get(ctx, path.Join(a.dayDir(projectID, day), manifestName))
+	if errors.Is(err, ErrNotFound) {
+		return &Manifest{ProjectID: projectID.String(), Date: day}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("corrupt manifest for %s: %w", day, err)
+	}
+	return manifest, nil
+}
+
+// Files returns the archived files of a project holding logs in [from, to).
+func (a *Archiver) Files(ctx context.Context, projectID uuid.UUID, from, to time.Time) ([]ManifestFile, error) {
+	var files []ManifestFile
+
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.AddDate(0, 0, 1) {
+		manifest, err := a.GetManifest(ctx, projectID, day.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range manifest.Files {
+			if file.MaxCreatedAt.Before(from) || !file.MinCreatedAt.Before(to) {
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// Read loads an archived file and verifies it against its manifest checksum.
+func (a *Archiver) Read(ctx context.Context, file ManifestFile) ([]Record, error) {
+	data, err := a.store.Get(ctx, file.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != file.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch for %s", file.Key)
+	}
+
+	return Decode(file.Format, data)
+}
+
+// dayDir turns 2006-01-02 into <prefix>/<project>/2006/01/02.
+func (a *Archiver) dayDir(projectID uuid.UUID, day string) string {
+	return path.Join(a.prefix, projectID.String(), day[0:4], day[5:7], day[8:10])
+}
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+// Record is one archived log line. The structured fields come last so files
+// written before they existed still decode, with the fields left empty.
+// Parquet stores the metadata as JSON, which unlike a map column doesn't
+// depend on map order and keeps exports resumable at a byte offset.
+type Record struct {
+	ID        string            `json:"id" parquet:"id"`
+	ProjectID string            `json:"project_id" parquet:"project_id"`
+	Category  string            `json:"category" parquet:"category"`
+	Message   string            `json:"message" parquet:"message"`
+	CreatedAt time.Time         `json:"created_at" parquet:"created_at,timestamp(microsecond)"`
+	Metadata  map[string]string `json:"metadata,omitempty" parquet:"metadata,optional,json"`
+	Tags      []string          `json:"tags,omitempty" parquet:"tags,list"`
+	TraceID   string            `json:"trace_id,omitempty" parquet:"trace_id,optional"`
+	PatternID string            `json:"pattern_id,omitempty" parquet:"pattern_id,optional"`
+	LoggedAt  *time.Time        `json:"logged_at,omitempty" parquet:"logged_at,optional"`
+}
+
+// extension returns the file extension used for a format.
+func extension(format string) string {
+	if format == FormatParquet {
+		return ".parquet"
+	}
+	return ".ndjson.gz"
+}
+
+func contentType(key string) string {
+	switch {
+	case strings.HasSuffix(key, ".json"):
+		return "application/json"
+	case strings.HasSuffix(key, ".gz"):
+		return "application/gzip"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Encode writes records as gzip compressed NDJSON or zstd compressed Parquet.
+func Encode(format string, records []Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case FormatNDJSON:
+		gz := gzip.NewWriter(&buf)
+		enc := json.NewEncoder(gz)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return nil, err
+			}
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+
+	case FormatParquet:
+		writer := parquet.NewGenericWriter[Record](&buf, parquet.Compression(&parquet.Zstd))
+		if _, err := writer.Write(records); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode reads back a file written by Encode.
+func Decode(format string, data []byte) ([]Record, error) {
+	switch format {
+	case FormatNDJSON:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		var records []Record
+		scanner := bufio.NewScanner(gz)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var record Record
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		return records, scanner.Err()
+
+	case FormatParquet:
+		reader := parquet.NewGenericReader[Record](bytes.NewReader(data))
+		defer reader.Close()
+
+		records := make([]Record, reader.NumRows())
+		n, err := reader.Read(records)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		return records[:n], nil
+
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+}
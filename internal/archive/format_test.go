@@ -0,0 +1,100 @@
+package archive
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)
+	loggedAt := createdAt.Add(-time.Second)
+	records := []Record{
+		{
+			ID:        "6b0f3c1e-3f7a-4c55-9d59-5a3c2b1e0f01",
+			ProjectID: "b5d4e3f2-1a2b-4c3d-8e9f-0a1b2c3d4e5f",
+			Category:  "error",
+			Message:   "payment failed",
+			CreatedAt: createdAt,
+			Metadata:  map[string]string{"order": "42", "user": "[REDACTED:email]"},
+			Tags:      []string{"billing", "retry"},
+			TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			PatternID: "a1b2c3d4e5f60718",
+			LoggedAt:  &loggedAt,
+		},
+		{
+			ID:        "6b0f3c1e-3f7a-4c55-9d59-5a3c2b1e0f02",
+			ProjectID: "b5d4e3f2-1a2b-4c3d-8e9f-0a1b2c3d4e5f",
+			Category:  "info",
+			Message:   "cache warmed up",
+			CreatedAt: createdAt.Add(time.Minute),
+		},
+	}
+
+	for _, format := range []string{FormatNDJSON, FormatParquet} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Encode(format, records)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			got, err := Decode(format, data)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(got) != len(records) {
+				t.Fatalf("decoded %d records, want %d", len(got), len(records))
+			}
+			for i := range records {
+				if !reflect.DeepEqual(normalize(got[i]), normalize(records[i])) {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], records[i])
+				}
+			}
+		})
+	}
+}
+
+// normalize clears empty metadata and tags, the formats don't tell empty
+// and missing apart.
+func normalize(r Record) Record {
+	if len(r.Metadata) == 0 {
+		r.Metadata = nil
+	}
+	if len(r.Tags) == 0 {
+		r.Tags = nil
+	}
+	return r
+}
+
+// TestDecodeBeforeStructuredFields reads a Parquet archive written before
+// records carried their structured fields.
+func TestDecodeBeforeStructuredFields(t *testing.T) {
+	type oldRecord struct {
+		ID        string    `parquet:"id"`
+		ProjectID string    `parquet:"project_id"`
+		Category  string    `parquet:"category"`
+		Message   string    `parquet:"message"`
+		CreatedAt time.Time `parquet:"created_at,timestamp(microsecond)"`
+	}
+	createdAt := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	writer := parquet.NewGenericWriter[oldRecord](&buf, parquet.Compression(&parquet.Zstd))
+	if _, err := writer.Write([]oldRecord{{ID: "1", ProjectID: "p", Category: "info", Message: "started", CreatedAt: createdAt}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Decode(FormatParquet, buf.Bytes())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := Record{ID: "1", ProjectID: "p", Category: "info", Message: "started", CreatedAt: createdAt}
+	if len(got) != 1 || !reflect.DeepEqual(normalize(got[0]), want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/reload"
+	"github.com/AjayShukla007/logsentinel/internal/repository/category"
+)
+
+// maxCategories bounds how many categories a project can configure
+const maxCategories = 50
+
+// Defaults are the categories of projects that didn't configure their own.
+var Defaults = []string{"debug", "info", "warning", "error", "event", "system"}
+
+// aliases map common level names to the categories they mean
+var aliases = map[string]string{
+	"trace":       "debug",
+	"dbg":         "debug",
+	"information": "info",
+	"notice":      "info",
+	"warn":        "warning",
+	"err":         "error",
+	"fatal":       "error",
+	"critical":    "error",
+	"crit":        "error",
+	"panic":       "error",
+	"emergency":   "error",
+	"alert":       "error",
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+var ErrInvalidCategory = errors.New("invalid category")
+
+// Aliases returns the category every alias maps to.
+func Aliases() map[string]string {
+	m := make(map[string]string, len(aliases))
+	for alias, name := range aliases {
+		m[alias] = name
+	}
+	return m
+}
+
+// Normalize lowercases a category and resolves aliases.
+func Normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// Validate normalizes the categories a project configures and checks them.
+func Validate(names []string) ([]string, error) {
+	if len(names) > maxCategories {
+		return nil, fmt.Errorf("at most %d categories are allowed", maxCategories)
+	}
+
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		n := Normalize(name)
+		if !validName.MatchString(n) {
+			return nil, fmt.Errorf("invalid category %q, use up to 64 lowercase letters, digits, '_', '.' or '-'", name)
+		}
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// Registry caches the categories of every project and reloads them
+// periodically.
+type Registry struct {
+	repo category.Repository
+
+	mu         sync.RWMutex
+	categories map[uuid.UUID]map[string]bool
+
+	defaults map[string]bool
+
+	reloads *reload.Loop
+}
+
+func NewRegistry(repo category.Repository) *Registry {
+	defaults := make(map[string]bool, len(Defaults))
+	for _, name := range Defaults {
+		defaults[name] = true
+	}
+
+	r := &Registry{
+		repo:       repo,
+		categories: make(map[uuid.UUID]map[string]bool),
+		defaults:   defaults,
+	}
+	r.reloads = reload.New("categories", r.Reload)
+	return r
+}
+
+// Start loads the categories and reloads them periodically until Stop is
+// called.
+func (r *Registry) Start(ctx context.Context) error {
+	return r.reloads.Start(ctx)
+}
+
+func (r *Registry) Stop(ctx context.Context) error {
+	return r.reloads.Stop(ctx)
+}
+
+// Reload replaces the cached categories with the ones in the database.
+func (r *Registry) Reload(ctx context.Context) error {
+	all, err := r.repo.ListAllCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("loading categories: %w", err)
+	}
+
+	categories := make(map[uuid.UUID]map[string]bool, len(all))
+	for projectID, names := range all {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			set[name] = true
+		}
+		categories[projectID] = set
+	}
+
+	r.mu.Lock()
+	r.categories = categories
+	r.mu.Unlock()
+	return nil
+}
+
+// Allowed reports whether logs of the project can use the normalized
+// category.
+func (r *Registry) Allowed(projectID uuid.UUID, name string) bool {
+	r.mu.RLock()
+	set, ok := r.categories[projectID]
+	r.mu.RUnlock()
+	if !ok {
+		set = r.defaults
+	}
+	return set[name]
+}
+
+// Resolve normalizes a category and checks that the project allows it.
+func (r *Registry) Resolve(projectID uuid.UUID, name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("%w: none given", ErrInvalidCategory)
+	}
+	n := Normalize(name)
+	if !r.Allowed(projectID, n) {
+		return "", fmt.Errorf("%w %q, the project accepts %s", ErrInvalidCategory, name, strings.Join(r.Categories(projectID), ", "))
+	}
+	return n, nil
+}
+
+// Categories returns the categories logs of the project can use.
+func (r *Registry) Categories(projectID uuid.UUID) []string {
+	r.mu.RLock()
+	set, ok := r.categories[projectID]
+	r.mu.RUnlock()
+	if !ok {
+		return append([]string(nil), Defaults...)
+	}
+
+	var names, custom []string
+	for _, name := range Defaults {
+		if set[name] {
+			names = append(names, name)
+		}
+	}
+	for name := range set {
+		if !r.defaults[name] {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
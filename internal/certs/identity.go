@@ -0,0 +1,35 @@
+package certs
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Identities returns the identities of the verified client certificate of
+// the connection behind ctx: its subject common name and its DNS, URI and
+// email SANs. It returns nil when the client presented no certificate or it
+// wasn't verified.
+func Identities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	leaf := info.State.VerifiedChains[0][0]
+	var identities []string
+	if leaf.Subject.CommonName != "" {
+		identities = append(identities, leaf.Subject.CommonName)
+	}
+	identities = append(identities, leaf.DNSNames...)
+	for _, uri := range leaf.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, leaf.EmailAddresses...)
+	return identities
+}
@@ -0,0 +1,173 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultReloadInterval is how often the files are checked for changes,
+// renewed certificates are picked up without a restart
+const defaultReloadInterval = 30 * time.Second
+
+// Reloader serves the TLS certificate of the gRPC listener and the CAs
+// client certificates are verified with, reloading them when their files
+// change on disk.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// NewReloader creates a reloader for the certificate and key files. Without
+// a client CA file client certificates aren't requested, requireClientCert
+// makes them mandatory instead of an alternative to API keys.
+func NewReloader(certFile, keyFile, clientCAFile string, requireClientCert bool) *Reloader {
+	clientAuth := tls.NoClientCert
+	if clientCAFile != "" {
+		clientAuth = tls.VerifyClientCertIfGiven
+		if requireClientCert {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		clientAuth:   clientAuth,
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start loads the files and checks them for changes periodically until Stop
+// is called.
+func (r *Reloader) Start(ctx context.Context) error {
+	if err := r.Reload(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(defaultReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.Reload(); err != nil {
+					slog.Error("Error reloading TLS certificates, keeping the previous ones", "err", err)
+				}
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *Reloader) Stop(ctx context.Context) error {
+	close(r.quit)
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reload loads the files again if any of them changed since the last load.
+func (r *Reloader) Reload() error {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	r.mu.RLock()
+	changed := r.cert == nil
+	for file, modTime := range modTimes {
+		if !r.modTimes[file].Equal(modTime) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("loading client CAs: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	reloaded := r.cert != nil
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	if reloaded {
+		slog.Info("Reloaded TLS certificates")
+	}
+	return nil
+}
+
+// TLSConfig returns the server TLS configuration, every handshake uses the
+// latest loaded certificate and client CAs.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientCAs:    r.clientCAs,
+				ClientAuth:   r.clientAuth,
+			}, nil
+		},
+	}
+}
+
+// VerifiesClients reports whether client certificates are verified.
+func (r *Reloader) VerifiesClients() bool {
+	return r.clientAuth != tls.NoClientCert
+}
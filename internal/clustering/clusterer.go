@@ -0,0 +1,280 @@
+package clustering
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/pattern"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+// defaultFlushInterval is how often pattern counts are written
+const defaultFlushInterval = 10 * time.Second
+
+const pruningJobName = "pattern_pruning"
+
+type Config struct {
+	// Depth is the depth of the parse tree, messages are routed by their
+	// first Depth-2 tokens
+	Depth int
+	// Similarity is the share of tokens a message must have in common with
+	// a template to join its cluster
+	Similarity float64
+	// MaxChildren caps the children of a tree node
+	MaxChildren int
+	// MaxClusters caps the patterns kept per project, logs matching none
+	// once it's reached get no pattern
+	MaxClusters int
+	// Retention is how long pattern counts are kept
+	Retention time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Depth:       4,
+		Similarity:  0.4,
+		MaxChildren: 100,
+		MaxClusters: 1000,
+		Retention:   14 * 24 * time.Hour,
+	}
+}
+
+// projectTree is the parse tree of one project, loaded from the stored
+// patterns the first time the project logs.
+type projectTree struct {
+	mu     sync.Mutex
+	loaded bool
+	drain  *drain
+	byID   map[string]*cluster
+}
+
+type countKey struct {
+	projectID uuid.UUID
+	patternID string
+	minute    time.Time
+}
+
+// Clusterer groups ingested messages into patterns per project. Matching
+// happens in memory on the ingestion path, counts are written periodically
+// like the usage rollups. Pattern ids derive from the project and the first
+// template of a cluster, so replicas seeing the same message agree on them
+// and an id stays put while its template generalizes.
+type Clusterer struct {
+	repo     pattern.Repository
+	config   Config
+	interval time.Duration
+
+	mu       sync.Mutex
+	projects map[uuid.UUID]*projectTree
+	pending  map[countKey]int64
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+func NewClusterer(repo pattern.Repository, config Config) *Clusterer {
+	return &Clusterer{
+		repo:     repo,
+		config:   config,
+		interval: defaultFlushInterval,
+		projects: make(map[uuid.UUID]*projectTree),
+		pending:  make(map[countKey]int64),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// RegisterJobs adds the daily pruning of old pattern counts.
+func (c *Clusterer) RegisterJobs(sched *scheduler.Scheduler) error {
+	return sched.Register(scheduler.Job{
+		Name:     pruningJobName,
+		Schedule: "45 3 * * *",
+		Timeout:  30 * time.Minute,
+		Jitter:   5 * time.Minute,
+		Run:      c.prune,
+	})
+}
+
+// Start flushes the counts every interval until Stop is called.
+func (c *Clusterer) Start() {
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.flush(context.Background())
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic flush and writes the remaining counts.
+func (c *Clusterer) Stop(ctx context.Context) error {
+	close(c.quit)
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return c.flush(ctx)
+}
+
+// Match returns the pattern id of a message, or an empty id when the
+// project has no room for another pattern.
+func (c *Clusterer) Match(ctx context.Context, projectID uuid.UUID, message string) string {
+	tree := c.tree(projectID)
+
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if !tree.loaded {
+		c.load(ctx, projectID, tree)
+	}
+
+	cl := tree.drain.add(tokenize(message), func(template string) string {
+		return patternID(projectID, template)
+	})
+	if cl == nil {
+		return ""
+	}
+	tree.byID[cl.id] = cl
+	return cl.id
+}
+
+// Record counts a stored log of a pattern.
+func (c *Clusterer) Record(projectID uuid.UUID, patternID string, createdAt time.Time) {
+	if patternID == "" {
+		return
+	}
+	k := countKey{projectID: projectID, patternID: patternID, minute: createdAt.Truncate(time.Minute)}
+
+	c.mu.Lock()
+	c.pending[k]++
+	c.mu.Unlock()
+}
+
+func (c *Clusterer) tree(projectID uuid.UUID) *projectTree {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tree, ok := c.projects[projectID]
+	if !ok {
+		tree = &projectTree{drain: newDrain(c.config), byID: make(map[string]*cluster)}
+		c.projects[projectID] = tree
+	}
+	return tree
+}
+
+// load seeds the tree with the stored patterns of the project. A failed
+// load is retried on the next message, patterns created meanwhile are kept.
+func (c *Clusterer) load(ctx context.Context, projectID uuid.UUID, tree *projectTree) {
+	patterns, err := c.repo.ListPatterns(ctx, projectID)
+	if err != nil {
+		slog.Error("Error loading log patterns", "project_id", projectID, "err", err)
+		return
+	}
+
+	for _, p := range patterns {
+		if _, ok := tree.byID[p.ID]; ok {
+			continue
+		}
+		cl := &cluster{id: p.ID, tokens: tokenize(p.Template)}
+		tree.drain.insert(cl)
+		tree.byID[cl.id] = cl
+	}
+	tree.loaded = true
+}
+
+func (c *Clusterer) flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[countKey]int64)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	type patternKey struct {
+		projectID uuid.UUID
+		patternID string
+	}
+	seen := make(map[patternKey]*pattern.Pattern)
+	var patterns []*pattern.Pattern
+	counts := make([]pattern.Count, 0, len(pending))
+
+	for k, n := range pending {
+		counts = append(counts, pattern.Count{
+			ProjectID: k.projectID,
+			PatternID: k.patternID,
+			Minute:    k.minute,
+			Logs:      n,
+		})
+
+		pk := patternKey{projectID: k.projectID, patternID: k.patternID}
+		if p, ok := seen[pk]; ok {
+			if k.minute.After(p.LastSeen) {
+				p.LastSeen = k.minute
+			}
+			continue
+		}
+		p := &pattern.Pattern{ID: k.patternID, ProjectID: k.projectID, Template: c.template(k.projectID, k.patternID), LastSeen: k.minute}
+		seen[pk] = p
+		patterns = append(patterns, p)
+	}
+
+	if err := c.repo.Save(ctx, patterns, counts); err != nil {
+		slog.Error("Error flushing log patterns, retrying next flush", "err", err)
+
+		c.mu.Lock()
+		for k, n := range pending {
+			c.pending[k] += n
+		}
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// template returns the current template of a pattern.
+func (c *Clusterer) template(projectID uuid.UUID, patternID string) string {
+	tree := c.tree(projectID)
+
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+
+	if cl, ok := tree.byID[patternID]; ok {
+		return cl.template()
+	}
+	return ""
+}
+
+func (c *Clusterer) prune(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+
+	pruned, err := c.repo.Prune(ctx, time.Now().UTC().Add(-c.config.Retention))
+	run.RowsAffected += pruned
+	if err != nil {
+		return fmt.Errorf("pruning log patterns: %w", err)
+	}
+	return nil
+}
+
+// patternID derives a short stable id from the project and a template.
+func patternID(projectID uuid.UUID, template string) string {
+	sum := sha256.Sum256([]byte(projectID.String() + "\x00" + template))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,161 @@
+package clustering
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Wildcard stands for the variable tokens of a template.
+const Wildcard = "<*>"
+
+// maxTokens caps the tokens of a message, the rest of a longer message is
+// folded into a trailing wildcard
+const maxTokens = 64
+
+type cluster struct {
+	id     string
+	tokens []string
+}
+
+func (c *cluster) template() string {
+	return strings.Join(c.tokens, " ")
+}
+
+type node struct {
+	children map[string]*node
+	clusters []*cluster
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+// drain is a parse tree after the Drain algorithm (He et al., 2017). Messages
+// are routed by token count and their first tokens to a leaf, then join the
+// most similar cluster of that leaf or start a new one. Joining replaces the
+// tokens that differ with wildcards, so templates only get more general.
+type drain struct {
+	depth       int
+	similarity  float64
+	maxChildren int
+	maxClusters int
+
+	root     map[int]*node
+	clusters int
+}
+
+func newDrain(config Config) *drain {
+	return &drain{
+		depth:       config.Depth,
+		similarity:  config.Similarity,
+		maxChildren: config.MaxChildren,
+		maxClusters: config.MaxClusters,
+		root:        make(map[int]*node),
+	}
+}
+
+// tokenize splits a message on whitespace and masks every token holding a
+// digit, which covers ids, numbers, addresses and timestamps before the
+// tree ever sees them.
+func tokenize(message string) []string {
+	tokens := strings.Fields(message)
+	if len(tokens) > maxTokens {
+		tokens = append(tokens[:maxTokens-1], Wildcard)
+	}
+	for i, token := range tokens {
+		if strings.IndexFunc(token, unicode.IsDigit) >= 0 {
+			tokens[i] = Wildcard
+		}
+	}
+	return tokens
+}
+
+// add assigns the tokens to a cluster, it returns nil once the tree holds
+// maxClusters and the tokens match none of them.
+func (d *drain) add(tokens []string, newID func(template string) string) *cluster {
+	leaf := d.leaf(tokens)
+
+	if c := d.match(leaf, tokens); c != nil {
+		for i, token := range tokens {
+			if c.tokens[i] != token {
+				c.tokens[i] = Wildcard
+			}
+		}
+		return c
+	}
+
+	if d.clusters >= d.maxClusters {
+		return nil
+	}
+	c := &cluster{tokens: append([]string(nil), tokens...)}
+	c.id = newID(c.template())
+	leaf.clusters = append(leaf.clusters, c)
+	d.clusters++
+	return c
+}
+
+// insert places a known cluster in the tree without matching it.
+func (d *drain) insert(c *cluster) {
+	leaf := d.leaf(c.tokens)
+	leaf.clusters = append(leaf.clusters, c)
+	d.clusters++
+}
+
+func (d *drain) leaf(tokens []string) *node {
+	n, ok := d.root[len(tokens)]
+	if !ok {
+		n = newNode()
+		d.root[len(tokens)] = n
+	}
+
+	for i := 0; i < d.depth-2 && i < len(tokens); i++ {
+		key := tokens[i]
+		child, ok := n.children[key]
+		if !ok {
+			// a crowded level sends new tokens down a shared wildcard path
+			if len(n.children) >= d.maxChildren {
+				key = Wildcard
+				child = n.children[key]
+			}
+			if child == nil {
+				child = newNode()
+				n.children[key] = child
+			}
+		}
+		n = child
+	}
+	return n
+}
+
+// match returns the most similar cluster of the leaf if it reaches the
+// similarity threshold. Wildcards match any token, ties go to the cluster
+// with more of them.
+func (d *drain) match(leaf *node, tokens []string) *cluster {
+	var best *cluster
+	bestScore, bestWildcards := -1.0, -1
+
+	for _, c := range leaf.clusters {
+		same, wildcards := 0, 0
+		for i, token := range c.tokens {
+			switch token {
+			case Wildcard:
+				wildcards++
+			case tokens[i]:
+				same++
+			}
+		}
+
+		score := 1.0
+		if len(tokens) > 0 {
+			score = float64(same+wildcards) / float64(len(tokens))
+		}
+		if score > bestScore || (score == bestScore && wildcards > bestWildcards) {
+			best, bestScore, bestWildcards = c, score, wildcards
+		}
+	}
+
+	if best == nil || bestScore < d.similarity {
+		return nil
+	}
+	return best
+}
@@ -0,0 +1,222 @@
+package clustering
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	long := strings.Repeat("word ", maxTokens+10)
+
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"empty", "", []string{}},
+		{"plain words", "cache warmed up", []string{"cache", "warmed", "up"}},
+		{"extra whitespace", "  cache \t warmed\nup ", []string{"cache", "warmed", "up"}},
+		{"digits masked", "request 42 took 13ms from 10.0.0.1", []string{"request", Wildcard, "took", Wildcard, "from", Wildcard}},
+		{"ids masked", "user u-7f3a logged in", []string{"user", Wildcard, "logged", "in"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.message)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("long message folded", func(t *testing.T) {
+		got := tokenize(long)
+		if len(got) != maxTokens {
+			t.Fatalf("got %d tokens, want %d", len(got), maxTokens)
+		}
+		if got[maxTokens-1] != Wildcard {
+			t.Errorf("last token = %q, want %q", got[maxTokens-1], Wildcard)
+		}
+	})
+}
+
+func TestDrainAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   func(*Config)
+		messages []string
+		// template of the cluster the last message joined, empty when it
+		// joined none
+		want     string
+		clusters int
+	}{
+		{
+			name:     "first message is its own template",
+			messages: []string{"login succeeded for alice"},
+			want:     "login succeeded for alice",
+			clusters: 1,
+		},
+		{
+			name:     "differing token becomes wildcard",
+			messages: []string{"login succeeded for alice", "login succeeded for bob"},
+			want:     "login succeeded for <*>",
+			clusters: 1,
+		},
+		{
+			name:     "masked numbers join without widening",
+			messages: []string{"request 123 took 45ms", "request 678 took 9ms"},
+			want:     "request <*> took <*>",
+			clusters: 1,
+		},
+		{
+			name:     "templates only get more general",
+			messages: []string{"login succeeded for alice", "login succeeded for bob", "login succeeded for alice"},
+			want:     "login succeeded for <*>",
+			clusters: 1,
+		},
+		{
+			name:     "token count routes apart",
+			messages: []string{"disk full", "disk full on sda"},
+			want:     "disk full on sda",
+			clusters: 2,
+		},
+		{
+			name:     "leading tokens route apart",
+			messages: []string{"user alice logged in", "user bob logged in"},
+			want:     "user bob logged in",
+			clusters: 2,
+		},
+		{
+			name:     "below similarity starts a new cluster",
+			messages: []string{"job started fetching remote feeds now", "job started with three retry attempts"},
+			want:     "job started with three retry attempts",
+			clusters: 2,
+		},
+		{
+			name:     "at similarity joins",
+			messages: []string{"job started fetching remote feeds", "job started with three retries"},
+			want:     "job started <*> <*> <*>",
+			clusters: 1,
+		},
+		{
+			name:     "full tree rejects new clusters",
+			config:   func(c *Config) { c.MaxClusters = 1 },
+			messages: []string{"cache warmed up", "queue drained fully"},
+			want:     "",
+			clusters: 1,
+		},
+		{
+			name:     "full tree still matches known clusters",
+			config:   func(c *Config) { c.MaxClusters = 1 },
+			messages: []string{"cache warmed up", "cache warmed down"},
+			want:     "cache warmed <*>",
+			clusters: 1,
+		},
+		{
+			name:     "crowded node routes through wildcard child",
+			config:   func(c *Config) { c.MaxChildren = 1 },
+			messages: []string{"alpha one two", "beta one two", "gamma one two"},
+			want:     "<*> one two",
+			clusters: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			d := newDrain(config)
+
+			var last *cluster
+			for _, message := range tt.messages {
+				last = d.add(tokenize(message), func(template string) string { return "id:" + template })
+			}
+
+			got := ""
+			if last != nil {
+				got = last.template()
+			}
+			if got != tt.want {
+				t.Errorf("template = %q, want %q", got, tt.want)
+			}
+			if d.clusters != tt.clusters {
+				t.Errorf("clusters = %d, want %d", d.clusters, tt.clusters)
+			}
+		})
+	}
+}
+
+func TestDrainAddKeepsID(t *testing.T) {
+	d := newDrain(DefaultConfig())
+	newID := func(template string) string { return "id:" + template }
+
+	first := d.add(tokenize("login succeeded for alice"), newID)
+	second := d.add(tokenize("login succeeded for bob"), newID)
+	if first != second {
+		t.Fatal("second message started a new cluster")
+	}
+	if second.id != "id:login succeeded for alice" {
+		t.Errorf("id = %q, want the id of the first template", second.id)
+	}
+}
+
+func TestDrainInsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		inserted [][]string
+		message  string
+		wantID   string
+		want     string
+	}{
+		{
+			name:     "known pattern matched",
+			inserted: [][]string{{"login", "succeeded", "for", Wildcard}},
+			message:  "login succeeded for carol",
+			wantID:   "p0",
+			want:     "login succeeded for <*>",
+		},
+		{
+			name: "tie goes to the more general template",
+			inserted: [][]string{
+				{"retry", "scheduled", "for", "payments"},
+				{"retry", "scheduled", Wildcard, "payments"},
+			},
+			message: "retry scheduled for payments",
+			wantID:  "p1",
+			want:    "retry scheduled <*> payments",
+		},
+		{
+			name:     "unknown pattern starts a new cluster",
+			inserted: [][]string{{"login", "succeeded", "for", Wildcard}},
+			message:  "login failed for carol",
+			wantID:   "new",
+			want:     "login failed for carol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDrain(DefaultConfig())
+			for i, tokens := range tt.inserted {
+				d.insert(&cluster{id: fmt.Sprintf("p%d", i), tokens: tokens})
+			}
+			if d.clusters != len(tt.inserted) {
+				t.Fatalf("clusters = %d after insert, want %d", d.clusters, len(tt.inserted))
+			}
+
+			c := d.add(tokenize(tt.message), func(string) string { return "new" })
+			if c == nil {
+				t.Fatal("message joined no cluster")
+			}
+			if c.id != tt.wantID {
+				t.Errorf("id = %q, want %q", c.id, tt.wantID)
+			}
+			if c.template() != tt.want {
+				t.Errorf("template = %q, want %q", c.template(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/robfig/cron/v3"
+
+	"github.com/AjayShukla007/logsentinel/internal/anomaly"
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/clustering"
+	"github.com/AjayShukla007/logsentinel/internal/logging"
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/ratelimit"
+	"github.com/AjayShukla007/logsentinel/internal/redact"
+	"github.com/AjayShukla007/logsentinel/internal/rehydrate"
+	"github.com/AjayShukla007/logsentinel/internal/rollup"
+	cronservice "github.com/AjayShukla007/logsentinel/internal/service/cron"
+	"github.com/AjayShukla007/logsentinel/internal/tracing"
+)
+
+// Config is the configuration of the server. Every setting has a key in the
+// config file, an environment variable and a flag named after its key, see
+// Load for how they are combined. Fields tagged secret are masked when the
+// configuration is printed.
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	TLS         TLSConfig         `yaml:"tls" toml:"tls"`
+	HTTP        HTTPConfig        `yaml:"http" toml:"http"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Retention   RetentionConfig   `yaml:"retention" toml:"retention"`
+	Rehydration RehydrationConfig `yaml:"rehydration" toml:"rehydration"`
+	Anomaly     AnomalyConfig     `yaml:"anomaly" toml:"anomaly"`
+	Archive     ArchiveConfig     `yaml:"archive" toml:"archive"`
+	Notify      NotifyConfig      `yaml:"notify" toml:"notify"`
+	Redact      RedactConfig      `yaml:"redact" toml:"redact"`
+}
+
+type ServerConfig struct {
+	ListenAddress   string        `yaml:"listen_address" toml:"listen_address" env:"LISTEN_ADDRESS" help:"address the gRPC server listens on"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" help:"how long shutdown waits for RPCs and background work"`
+	Reflection      bool          `yaml:"reflection" toml:"reflection" env:"ENABLE_GRPC_REFLECTION" help:"register the gRPC reflection service"`
+	AdminToken      string        `yaml:"admin_token" toml:"admin_token" env:"ADMIN_TOKEN" secret:"true" help:"token required by the admin RPCs, they are disabled without one"`
+}
+
+// TLSConfig enables TLS on the gRPC listener when a certificate and key are
+// set, the files are reloaded when they change. With a client CA clients can
+// authenticate with a certificate instead of an API key.
+type TLSConfig struct {
+	CertFile          string `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE" help:"PEM certificate of the gRPC listener"`
+	KeyFile           string `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE" help:"PEM private key of the gRPC listener"`
+	ClientCAFile      string `yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE" help:"PEM CAs client certificates are verified with, enables mutual TLS"`
+	RequireClientCert bool   `yaml:"require_client_cert" toml:"require_client_cert" env:"TLS_REQUIRE_CLIENT_CERT" help:"reject clients without a verified certificate"`
+}
+
+// Enabled reports whether the listener serves TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// HTTPConfig is the HTTP listener serving /metrics next to the gRPC server.
+type HTTPConfig struct {
+	ListenAddress string `yaml:"listen_address" toml:"listen_address" env:"HTTP_LISTEN_ADDRESS" help:"address the HTTP server listens on, empty disables it"`
+}
+
+// LogConfig is the server log, records go to stderr.
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" help:"minimum level logged: debug, info, warn or error"`
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" help:"output format: text or json"`
+}
+
+// TracingConfig exports OpenTelemetry spans of RPCs and database queries.
+// Trace context sent by clients is propagated whatever the exporter.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" help:"where spans are sent: none, stdout or otlp"`
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" help:"OTLP gRPC collector, host:port or URL"`
+	Insecure    bool    `yaml:"insecure" toml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE" help:"connect to the collector without TLS"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" help:"share of new traces recorded, from 0 to 1"`
+	ServiceName string  `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" help:"service name reported with the spans"`
+}
+
+type DatabaseConfig struct {
+	Host string `yaml:"host" toml:"host" env:"DB_HOST" help:"database host"`
+	Port int    `yaml:"port" toml:"port" env:"DB_PORT" help:"database port"`
+	User string `yaml:"user" toml:"user" env:"DB_USER" help:"database user"`
+	// Password is replaced by the contents of PasswordFile when that is set
+	Password     string `yaml:"password" toml:"password" env:"DB_PASSWORD" secret:"true" help:"database password"`
+	PasswordFile string `yaml:"password_file" toml:"password_file" env:"DB_PASSWORD_FILE" help:"file holding the database password, such as a Docker secret"`
+	Name         string `yaml:"name" toml:"name" env:"DB_NAME" help:"database name"`
+	SSLMode      string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE" help:"disable, allow, prefer, require, verify-ca or verify-full"`
+	SSLRootCert  string `yaml:"sslrootcert" toml:"sslrootcert" env:"DB_SSLROOTCERT" help:"PEM CAs the database certificate is verified with"`
+	SSLCert      string `yaml:"sslcert" toml:"sslcert" env:"DB_SSLCERT" help:"PEM client certificate presented to the database"`
+	SSLKey       string `yaml:"sslkey" toml:"sslkey" env:"DB_SSLKEY" help:"PEM private key of the database client certificate"`
+
+	MaxConns        int           `yaml:"max_conns" toml:"max_conns" env:"DB_MAX_CONNS" help:"maximum size of the connection pool"`
+	MinConns        int           `yaml:"min_conns" toml:"min_conns" env:"DB_MIN_CONNS" help:"connections the pool keeps open when idle"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME" help:"age after which a connection is closed"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME" help:"idle time after which a connection is closed"`
+
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"AUTO_MIGRATE" help:"apply pending migrations on startup"`
+}
+
+// DSN returns the connection string of the database.
+func (c DatabaseConfig) DSN() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:   "/" + c.Name,
+	}
+
+	query := url.Values{"sslmode": {c.SSLMode}}
+	for key, value := range map[string]string{"sslrootcert": c.SSLRootCert, "sslcert": c.SSLCert, "sslkey": c.SSLKey} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Address returns the host and port of the database, for logging.
+func (c DatabaseConfig) Address() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// PoolConfig returns the connection pool configuration of the database.
+func (c DatabaseConfig) PoolConfig() (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(c.DSN())
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.MaxConns = int32(c.MaxConns)
+	poolConfig.MinConns = int32(c.MinConns)
+	poolConfig.MaxConnLifetime = c.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = c.MaxConnIdleTime
+	// log timestamps are TIMESTAMP without a time zone defaulting to the
+	// session's CURRENT_TIMESTAMP, pinning sessions to UTC keeps them in line
+	// with the UTC day and week bounds of the logs partitions
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	return poolConfig, nil
+}
+
+type RateLimitConfig struct {
+	FreeLogsPerMinute int `yaml:"free_logs_per_minute" toml:"free_logs_per_minute" env:"RATE_LIMIT_FREE_LOGS_PER_MINUTE" help:"logs a free account may send per minute"`
+}
+
+// RetentionConfig controls the retention cleanup, partition maintenance and
+// how long rollups and patterns are kept.
+type RetentionConfig struct {
+	Schedule          string        `yaml:"schedule" toml:"schedule" env:"RETENTION_SCHEDULE" help:"cron expression of the retention cleanup"`
+	PartitionSchedule string        `yaml:"partition_schedule" toml:"partition_schedule" env:"PARTITION_SCHEDULE" help:"cron expression of partition maintenance"`
+	BatchSize         int           `yaml:"batch_size" toml:"batch_size" env:"RETENTION_BATCH_SIZE" help:"maximum rows removed by one DELETE"`
+	BatchPause        time.Duration `yaml:"batch_pause" toml:"batch_pause" env:"RETENTION_BATCH_PAUSE" help:"pause between cleanup batches"`
+	MaxRunTime        time.Duration `yaml:"max_run_time" toml:"max_run_time" env:"RETENTION_MAX_RUN_TIME" help:"time budget of one cleanup run"`
+	PartitionInterval string        `yaml:"partition_interval" toml:"partition_interval" env:"LOG_PARTITION_INTERVAL" help:"range of one logs partition, daily or weekly"`
+	PartitionsAhead   int           `yaml:"partitions_ahead" toml:"partitions_ahead" env:"LOG_PARTITIONS_AHEAD" help:"future partitions kept created"`
+	ArchivePlans      []string      `yaml:"archive_plans" toml:"archive_plans" env:"ARCHIVE_PLANS" help:"account types whose expired logs are archived"`
+
+	RollupMinutes time.Duration `yaml:"rollup_minutes" toml:"rollup_minutes" env:"ROLLUP_MINUTE_RETENTION" help:"how long minute rollups are kept"`
+	RollupHours   time.Duration `yaml:"rollup_hours" toml:"rollup_hours" env:"ROLLUP_HOUR_RETENTION" help:"how long hour rollups are kept"`
+	Patterns      time.Duration `yaml:"patterns" toml:"patterns" env:"PATTERN_RETENTION" help:"how long pattern counts are kept"`
+}
+
+type RehydrationConfig struct {
+	DefaultTTL time.Duration `yaml:"default_ttl" toml:"default_ttl" env:"REHYDRATION_TTL" help:"lifetime of rehydrated logs when the request doesn't say"`
+	MaxTTL     time.Duration `yaml:"max_ttl" toml:"max_ttl" env:"REHYDRATION_MAX_TTL" help:"maximum lifetime of rehydrated logs"`
+}
+
+type AnomalyConfig struct {
+	Threshold float64 `yaml:"threshold" toml:"threshold" env:"ANOMALY_THRESHOLD" help:"score from which an hour is an anomaly"`
+	MinCount  int64   `yaml:"min_count" toml:"min_count" env:"ANOMALY_MIN_COUNT" help:"counts below which hours aren't scored"`
+}
+
+type ArchiveConfig struct {
+	Enabled     bool   `yaml:"enabled" toml:"enabled" env:"ARCHIVE_ENABLED" help:"archive expired logs before deleting them"`
+	Backend     string `yaml:"backend" toml:"backend" env:"ARCHIVE_BACKEND" help:"local or s3"`
+	Dir         string `yaml:"dir" toml:"dir" env:"ARCHIVE_DIR" help:"directory of the local backend"`
+	Format      string `yaml:"format" toml:"format" env:"ARCHIVE_FORMAT" help:"ndjson or parquet"`
+	Prefix      string `yaml:"prefix" toml:"prefix" env:"ARCHIVE_PREFIX" help:"prefix of archived objects"`
+	S3Endpoint  string `yaml:"s3_endpoint" toml:"s3_endpoint" env:"ARCHIVE_S3_ENDPOINT" help:"endpoint of the s3 backend"`
+	S3Bucket    string `yaml:"s3_bucket" toml:"s3_bucket" env:"ARCHIVE_S3_BUCKET" help:"bucket of the s3 backend"`
+	S3Region    string `yaml:"s3_region" toml:"s3_region" env:"ARCHIVE_S3_REGION" help:"region of the s3 backend"`
+	S3AccessKey string `yaml:"s3_access_key" toml:"s3_access_key" env:"ARCHIVE_S3_ACCESS_KEY" secret:"true" help:"access key of the s3 backend"`
+	S3SecretKey string `yaml:"s3_secret_key" toml:"s3_secret_key" env:"ARCHIVE_S3_SECRET_KEY" secret:"true" help:"secret key of the s3 backend"`
+	S3UseSSL    bool   `yaml:"s3_use_ssl" toml:"s3_use_ssl" env:"ARCHIVE_S3_USE_SSL" help:"connect to the s3 backend over TLS"`
+}
+
+type NotifyConfig struct {
+	TemplateDir     string        `yaml:"template_dir" toml:"template_dir" env:"NOTIFY_TEMPLATE_DIR" help:"directory overriding the notification templates"`
+	WebhookURL      string        `yaml:"webhook_url" toml:"webhook_url" env:"NOTIFY_WEBHOOK_URL" help:"URL events are posted to"`
+	WebhookSecret   string        `yaml:"webhook_secret" toml:"webhook_secret" env:"NOTIFY_WEBHOOK_SECRET" secret:"true" help:"key signing webhook payloads"`
+	SlackWebhookURL string        `yaml:"slack_webhook_url" toml:"slack_webhook_url" env:"NOTIFY_SLACK_WEBHOOK_URL" secret:"true" help:"Slack incoming webhook URL"`
+	SMTPHost        string        `yaml:"smtp_host" toml:"smtp_host" env:"NOTIFY_SMTP_HOST" help:"SMTP server of email notifications"`
+	SMTPPort        int           `yaml:"smtp_port" toml:"smtp_port" env:"NOTIFY_SMTP_PORT" help:"SMTP port"`
+	SMTPUsername    string        `yaml:"smtp_username" toml:"smtp_username" env:"NOTIFY_SMTP_USERNAME" help:"SMTP user"`
+	SMTPPassword    string        `yaml:"smtp_password" toml:"smtp_password" env:"NOTIFY_SMTP_PASSWORD" secret:"true" help:"SMTP password"`
+	SMTPFrom        string        `yaml:"smtp_from" toml:"smtp_from" env:"NOTIFY_SMTP_FROM" help:"sender of email notifications"`
+	SMTPTo          []string      `yaml:"smtp_to" toml:"smtp_to" env:"NOTIFY_SMTP_TO" help:"recipients of email notifications"`
+	MaxAttempts     int           `yaml:"max_attempts" toml:"max_attempts" env:"NOTIFY_MAX_ATTEMPTS" help:"delivery attempts per event and channel"`
+	RatePerMinute   int           `yaml:"rate_per_minute" toml:"rate_per_minute" env:"NOTIFY_RATE_PER_MINUTE" help:"events a channel delivers per minute"`
+	Burst           int           `yaml:"burst" toml:"burst" env:"NOTIFY_BURST" help:"events a channel may deliver at once"`
+	Cooldown        time.Duration `yaml:"cooldown" toml:"cooldown" env:"NOTIFY_COOLDOWN" help:"period repeats of an event are suppressed for"`
+}
+
+type RedactConfig struct {
+	DefaultAction string   `yaml:"default_action" toml:"default_action" env:"REDACT_DEFAULT_ACTION" help:"action of built-in detectors, mask, hash or drop"`
+	Disabled      []string `yaml:"disabled" toml:"disabled" env:"REDACT_DISABLED" help:"built-in detectors that are off unless a project enables them"`
+	HashKey       string   `yaml:"hash_key" toml:"hash_key" env:"REDACT_HASH_KEY" secret:"true" help:"key of the hash action, random per restart without one"`
+}
+
+// Default returns the configuration used for settings no source sets.
+func Default() *Config {
+	cronConfig := cronservice.DefaultConfig()
+	rollupConfig := rollup.DefaultConfig()
+	rehydrateConfig := rehydrate.DefaultConfig()
+	anomalyConfig := anomaly.DefaultConfig()
+	notifyConfig := notify.DefaultConfig()
+	tracingConfig := tracing.DefaultConfig()
+
+	return &Config{
+		Server: ServerConfig{
+			ListenAddress:   ":50051",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		HTTP: HTTPConfig{
+			ListenAddress: ":8080",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracingConfig.Exporter,
+			Endpoint:    tracingConfig.Endpoint,
+			SampleRatio: tracingConfig.SampleRatio,
+			ServiceName: tracingConfig.ServiceName,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "logsentinel",
+			SSLMode:         "prefer",
+			MaxConns:        20,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			FreeLogsPerMinute: ratelimit.DefaultFreeLogsPerMinute,
+		},
+		Retention: RetentionConfig{
+			Schedule:          cronConfig.RetentionSchedule,
+			PartitionSchedule: cronConfig.PartitionSchedule,
+			BatchSize:         cronConfig.BatchSize,
+			BatchPause:        cronConfig.BatchPause,
+			MaxRunTime:        cronConfig.MaxRunTime,
+			PartitionInterval: cronConfig.PartitionInterval,
+			PartitionsAhead:   cronConfig.PartitionsAhead,
+			ArchivePlans:      cronConfig.ArchivePlans,
+			RollupMinutes:     rollupConfig.MinuteRetention,
+			RollupHours:       rollupConfig.HourRetention,
+			Patterns:          clustering.DefaultConfig().Retention,
+		},
+		Rehydration: RehydrationConfig{
+			DefaultTTL: rehydrateConfig.DefaultTTL,
+			MaxTTL:     rehydrateConfig.MaxTTL,
+		},
+		Anomaly: AnomalyConfig{
+			Threshold: anomalyConfig.Threshold,
+			MinCount:  anomalyConfig.MinCount,
+		},
+		Archive: ArchiveConfig{
+			Backend:  archive.BackendLocal,
+			Dir:      "archive",
+			Format:   archive.FormatNDJSON,
+			Prefix:   "logs",
+			S3UseSSL: true,
+		},
+		Notify: NotifyConfig{
+			SMTPPort:      587,
+			SMTPFrom:      "logsentinel@localhost",
+			MaxAttempts:   notifyConfig.MaxAttempts,
+			RatePerMinute: notifyConfig.RatePerMinute,
+			Burst:         notifyConfig.Burst,
+			Cooldown:      notifyConfig.Cooldown,
+		},
+		Redact: RedactConfig{
+			DefaultAction: redact.DefaultConfig().DefaultAction,
+		},
+	}
+}
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// Validate checks the configuration and returns every problem it finds.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.ListenAddress); err != nil {
+		errs = append(errs, fmt.Errorf("server.listen_address: %w", err))
+	}
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	if c.HTTP.ListenAddress != "" {
+		if _, _, err := net.SplitHostPort(c.HTTP.ListenAddress); err != nil {
+			errs = append(errs, fmt.Errorf("http.listen_address: %w", err))
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON, "log.format must be text or json")
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterOTLP:
+		check(c.Tracing.Endpoint != "", "tracing.endpoint is required by the otlp exporter")
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+	check(c.Tracing.ServiceName != "", "tracing.service_name must not be empty")
+
+	if c.TLS.Enabled() {
+		check(c.TLS.CertFile != "" && c.TLS.KeyFile != "", "tls.cert_file and tls.key_file must be set together")
+		checkFile(&errs, "tls.cert_file", c.TLS.CertFile)
+		checkFile(&errs, "tls.key_file", c.TLS.KeyFile)
+		checkFile(&errs, "tls.client_ca_file", c.TLS.ClientCAFile)
+	} else {
+		check(c.TLS.ClientCAFile == "", "tls.client_ca_file requires tls.cert_file and tls.key_file")
+	}
+	check(!c.TLS.RequireClientCert || c.TLS.ClientCAFile != "", "tls.require_client_cert requires tls.client_ca_file")
+
+	db := c.Database
+	check(db.Host != "", "database.host is required")
+	check(db.Port > 0 && db.Port < 65536, "database.port must be between 1 and 65535")
+	check(db.User != "", "database.user is required")
+	check(db.Name != "", "database.name is required")
+	check(sslModes[db.SSLMode], "database.sslmode %q must be disable, allow, prefer, require, verify-ca or verify-full", db.SSLMode)
+	check((db.SSLCert == "") == (db.SSLKey == ""), "database.sslcert and database.sslkey must be set together")
+	checkFile(&errs, "database.sslrootcert", db.SSLRootCert)
+	checkFile(&errs, "database.sslcert", db.SSLCert)
+	checkFile(&errs, "database.sslkey", db.SSLKey)
+	check(db.MaxConns > 0, "database.max_conns must be positive")
+	check(db.MinConns >= 0 && db.MinConns <= db.MaxConns, "database.min_conns must be between 0 and database.max_conns")
+	check(db.MaxConnLifetime >= 0, "database.max_conn_lifetime must not be negative")
+	check(db.MaxConnIdleTime >= 0, "database.max_conn_idle_time must not be negative")
+
+	check(c.RateLimit.FreeLogsPerMinute > 0, "rate_limit.free_logs_per_minute must be positive")
+
+	r := c.Retention
+	if _, err := cron.ParseStandard(r.Schedule); err != nil {
+		errs = append(errs, fmt.Errorf("retention.schedule: %w", err))
+	}
+	if _, err := cron.ParseStandard(r.PartitionSchedule); err != nil {
+		errs = append(errs, fmt.Errorf("retention.partition_schedule: %w", err))
+	}
+	check(r.BatchSize > 0, "retention.batch_size must be positive")
+	check(r.BatchPause >= 0, "retention.batch_pause must not be negative")
+	check(r.MaxRunTime > 0, "retention.max_run_time must be positive")
+	check(r.PartitionInterval == cronservice.PartitionDaily || r.PartitionInterval == cronservice.PartitionWeekly,
+		"retention.partition_interval %q must be daily or weekly", r.PartitionInterval)
+	check(r.PartitionsAhead > 0, "retention.partitions_ahead must be positive")
+	check(r.RollupMinutes > 0, "retention.rollup_minutes must be positive")
+	check(r.RollupHours > 0, "retention.rollup_hours must be positive")
+	check(r.Patterns > 0, "retention.patterns must be positive")
+
+	check(c.Rehydration.DefaultTTL > 0, "rehydration.default_ttl must be positive")
+	check(c.Rehydration.MaxTTL >= c.Rehydration.DefaultTTL, "rehydration.max_ttl must not be shorter than rehydration.default_ttl")
+
+	check(c.Anomaly.Threshold > 0, "anomaly.threshold must be positive")
+	check(c.Anomaly.MinCount >= 0, "anomaly.min_count must not be negative")
+
+	if a := c.Archive; a.Enabled {
+		check(a.Backend == archive.BackendLocal || a.Backend == archive.BackendS3, "archive.backend %q must be local or s3", a.Backend)
+		check(a.Format == archive.FormatNDJSON || a.Format == archive.FormatParquet, "archive.format %q must be ndjson or parquet", a.Format)
+		check(a.Backend != archive.BackendLocal || a.Dir != "", "archive.dir is required by the local backend")
+		check(a.Backend != archive.BackendS3 || (a.S3Endpoint != "" && a.S3Bucket != ""), "archive.s3_endpoint and archive.s3_bucket are required by the s3 backend")
+	}
+
+	n := c.Notify
+	if n.SMTPHost != "" {
+		check(len(n.SMTPTo) > 0, "notify.smtp_to is required when notify.smtp_host is set")
+		check(n.SMTPPort > 0 && n.SMTPPort < 65536, "notify.smtp_port must be between 1 and 65535")
+	}
+	check(n.MaxAttempts > 0, "notify.max_attempts must be positive")
+	check(n.RatePerMinute > 0, "notify.rate_per_minute must be positive")
+	check(n.Burst > 0, "notify.burst must be positive")
+	check(n.Cooldown >= 0, "notify.cooldown must not be negative")
+
+	check(redact.ValidAction(c.Redact.DefaultAction), "redact.default_action %q must be mask, hash or drop", c.Redact.DefaultAction)
+	for _, name := range c.Redact.Disabled {
+		check(redact.IsBuiltin(name), "redact.disabled: unknown detector %q", name)
+	}
+
+	return errors.Join(errs...)
+}
+
+func checkFile(errs *[]error, key, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+	}
+}
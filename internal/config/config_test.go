@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	certFile := writeFile(t, "server.crt", "")
+
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		// errs are substrings of the expected error, none when the
+		// configuration is valid
+		errs []string
+	}{
+		{"defaults", func(c *Config) {}, nil},
+		{"http disabled", func(c *Config) { c.HTTP.ListenAddress = "" }, nil},
+		{"listen address", func(c *Config) { c.Server.ListenAddress = "50051" }, []string{"server.listen_address"}},
+		{"shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, []string{"server.shutdown_timeout must be positive"}},
+		{"log level", func(c *Config) { c.Log.Level = "loud" }, []string{"log.level"}},
+		{"log format", func(c *Config) { c.Log.Format = "xml" }, []string{"log.format must be text or json"}},
+		{"otlp endpoint", func(c *Config) {
+			c.Tracing.Exporter = "otlp"
+			c.Tracing.Endpoint = ""
+		}, []string{"tracing.endpoint is required"}},
+		{"trace exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }, []string{`tracing.exporter must be none, stdout or otlp, got "jaeger"`}},
+		{"sample ratio", func(c *Config) { c.Tracing.SampleRatio = 1.5 }, []string{"tracing.sample_ratio"}},
+		{"tls cert without key", func(c *Config) { c.TLS.CertFile = certFile }, []string{"tls.cert_file and tls.key_file must be set together"}},
+		{"missing tls file", func(c *Config) {
+			c.TLS.CertFile = certFile
+			c.TLS.KeyFile = "/nonexistent/server.key"
+		}, []string{"tls.key_file"}},
+		{"client ca without tls", func(c *Config) { c.TLS.ClientCAFile = certFile }, []string{"tls.client_ca_file requires"}},
+		{"client cert without ca", func(c *Config) { c.TLS.RequireClientCert = true }, []string{"tls.require_client_cert requires"}},
+		{"database port", func(c *Config) { c.Database.Port = 70000 }, []string{"database.port"}},
+		{"sslmode", func(c *Config) { c.Database.SSLMode = "always" }, []string{`database.sslmode "always"`}},
+		{"ssl cert without key", func(c *Config) { c.Database.SSLCert = certFile }, []string{"database.sslcert and database.sslkey"}},
+		{"pool bounds", func(c *Config) { c.Database.MinConns = c.Database.MaxConns + 1 }, []string{"database.min_conns"}},
+		{"cron schedule", func(c *Config) { c.Retention.Schedule = "every night" }, []string{"retention.schedule"}},
+		{"partition interval", func(c *Config) { c.Retention.PartitionInterval = "monthly" }, []string{`retention.partition_interval "monthly"`}},
+		{"rehydration ttl", func(c *Config) { c.Rehydration.MaxTTL = c.Rehydration.DefaultTTL - time.Hour }, []string{"rehydration.max_ttl"}},
+		{"archive off skips backend", func(c *Config) { c.Archive.Backend = "ftp" }, nil},
+		{"archive backend", func(c *Config) {
+			c.Archive.Enabled = true
+			c.Archive.Backend = "ftp"
+		}, []string{`archive.backend "ftp"`}},
+		{"s3 bucket", func(c *Config) {
+			c.Archive.Enabled = true
+			c.Archive.Backend = "s3"
+		}, []string{"archive.s3_endpoint and archive.s3_bucket"}},
+		{"smtp recipients", func(c *Config) { c.Notify.SMTPHost = "smtp.example.com" }, []string{"notify.smtp_to is required"}},
+		{"redact action", func(c *Config) { c.Redact.DefaultAction = "shred" }, []string{`redact.default_action "shred"`}},
+		{"redact detector", func(c *Config) { c.Redact.Disabled = []string{"email", "ssn"} }, []string{`unknown detector "ssn"`}},
+		{"every problem reported", func(c *Config) {
+			c.Log.Level = "loud"
+			c.Database.Host = ""
+			c.Notify.Burst = 0
+		}, []string{"log.level", "database.host is required", "notify.burst must be positive"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.change(cfg)
+
+			err := cfg.Validate()
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("no error, want %q", tt.errs)
+			}
+			for _, want := range tt.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolConfig(t *testing.T) {
+	cfg := Default()
+	cfg.Database.MaxConns = 7
+
+	poolConfig, err := cfg.Database.PoolConfig()
+	if err != nil {
+		t.Fatalf("PoolConfig: %v", err)
+	}
+	if poolConfig.MaxConns != 7 {
+		t.Errorf("max conns = %d, want 7", poolConfig.MaxConns)
+	}
+	if tz := poolConfig.ConnConfig.RuntimeParams["timezone"]; tz != "UTC" {
+		t.Errorf("session time zone = %q, want UTC to match the partition bounds", tz)
+	}
+}
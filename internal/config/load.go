@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the path of the config
+// file, the -config flag overrides it.
+const FileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from the defaults, the config file, the
+// environment and the flags in args, each overriding the ones before. Empty
+// environment variables count as unset. It returns the arguments left after
+// the flags and fails when the configuration isn't valid.
+func Load(name string, args []string) (*Config, []string, error) {
+	cfg := Default()
+	settings := settingsOf(cfg)
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	path := fs.String("config", os.Getenv(FileEnv), "config file, YAML (.yaml, .yml) or TOML (.toml)")
+	byKey := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = s
+		fs.Var(&flagValue{raw: s.String(), isBool: s.value.Kind() == reflect.Bool}, s.key, fmt.Sprintf("%s (env %s)", s.help, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *path != "" {
+		if err := loadFile(*path, cfg); err != nil {
+			return nil, nil, fmt.Errorf("config file %s: %w", *path, err)
+		}
+	}
+
+	for _, s := range settings {
+		if raw := os.Getenv(s.env); raw != "" {
+			if err := s.Set(raw); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		s, ok := byKey[f.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		if err := s.Set(f.Value.String()); err != nil {
+			flagErr = fmt.Errorf("invalid -%s: %w", f.Name, err)
+		}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	if file := cfg.Database.PasswordFile; file != "" {
+		password, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("database.password_file: %w", err)
+		}
+		cfg.Database.Password = strings.TrimSpace(string(password))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// loadFile decodes a YAML or TOML file into cfg, keys it doesn't know are
+// rejected so typos don't go unnoticed.
+func loadFile(path string, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+
+	case ".toml":
+		meta, err := toml.DecodeFile(path, cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			return fmt.Errorf("unknown keys %s", strings.Join(keys, ", "))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown format, use .yaml, .yml or .toml")
+	}
+}
+
+// Redacted returns a copy of the configuration with its secrets masked.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, s := range settingsOf(&redacted) {
+		if s.secret && s.value.String() != "" {
+			s.value.SetString("********")
+		}
+	}
+	return &redacted
+}
+
+// String renders the configuration as YAML with its secrets masked.
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting is a single configuration field, key is its dotted path in the
+// config file and the name of its flag.
+type setting struct {
+	key    string
+	env    string
+	help   string
+	secret bool
+	value  reflect.Value
+}
+
+// settingsOf lists the settings of cfg, their values point into cfg.
+func settingsOf(cfg *Config) []setting {
+	var settings []setting
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		prefix := sections.Type().Field(i).Tag.Get("yaml")
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			settings = append(settings, setting{
+				key:    prefix + "." + field.Tag.Get("yaml"),
+				env:    field.Tag.Get("env"),
+				help:   field.Tag.Get("help"),
+				secret: field.Tag.Get("secret") == "true",
+				value:  section.Field(j),
+			})
+		}
+	}
+	return settings
+}
+
+// Set parses raw into the setting, lists are comma separated.
+func (s setting) Set(raw string) error {
+	v := s.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+func (s setting) String() string {
+	v := s.value
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// flagValue keeps the raw value of a flag, flags are applied after the
+// config file and the environment so they can't be set right away.
+type flagValue struct {
+	raw    string
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.raw
+}
+
+func (f *flagValue) Set(raw string) error {
+	f.raw = raw
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
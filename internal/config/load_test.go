@@ -0,0 +1,312 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets every setting's environment variable for the test, empty
+// variables count as unset.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(FileEnv, "")
+	for _, s := range settingsOf(Default()) {
+		t.Setenv(s.env, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		// files are written to a temporary directory, {name} in env and
+		// args is replaced by the path of the file
+		files map[string]string
+		env   map[string]string
+		args  []string
+		// want changes the defaults into the expected configuration
+		want     func(c *Config)
+		wantArgs []string
+	}{
+		{
+			name: "defaults",
+			want: func(c *Config) {},
+		},
+		{
+			name:  "file overrides defaults",
+			files: map[string]string{"config.yaml": "log:\n  level: debug\nserver:\n  shutdown_timeout: 5s\n"},
+			args:  []string{"-config", "{config.yaml}"},
+			want: func(c *Config) {
+				c.Log.Level = "debug"
+				c.Server.ShutdownTimeout = 5 * time.Second
+			},
+		},
+		{
+			name:  "toml file",
+			files: map[string]string{"config.toml": "[log]\nlevel = \"debug\"\n\n[database]\nport = 6543\n"},
+			args:  []string{"-config", "{config.toml}"},
+			want: func(c *Config) {
+				c.Log.Level = "debug"
+				c.Database.Port = 6543
+			},
+		},
+		{
+			name:  "env overrides file",
+			files: map[string]string{"config.yaml": "log:\n  level: debug\n"},
+			env:   map[string]string{"LOG_LEVEL": "warn"},
+			args:  []string{"-config", "{config.yaml}"},
+			want:  func(c *Config) { c.Log.Level = "warn" },
+		},
+		{
+			name:  "flag overrides env",
+			files: map[string]string{"config.yaml": "log:\n  level: debug\n"},
+			env:   map[string]string{"LOG_LEVEL": "warn"},
+			args:  []string{"-config", "{config.yaml}", "-log.level", "error"},
+			want:  func(c *Config) { c.Log.Level = "error" },
+		},
+		{
+			name:  "empty env counts as unset",
+			files: map[string]string{"config.yaml": "log:\n  level: debug\n"},
+			env:   map[string]string{"LOG_LEVEL": ""},
+			args:  []string{"-config", "{config.yaml}"},
+			want:  func(c *Config) { c.Log.Level = "debug" },
+		},
+		{
+			name:  "file from env",
+			files: map[string]string{"config.yaml": "log:\n  level: debug\n"},
+			env:   map[string]string{FileEnv: "{config.yaml}"},
+			want:  func(c *Config) { c.Log.Level = "debug" },
+		},
+		{
+			name: "config flag overrides file from env",
+			files: map[string]string{
+				"env.yaml":  "log:\n  level: debug\n",
+				"flag.yaml": "log:\n  level: warn\n",
+			},
+			env:  map[string]string{FileEnv: "{env.yaml}"},
+			args: []string{"-config={flag.yaml}"},
+			want: func(c *Config) { c.Log.Level = "warn" },
+		},
+		{
+			name: "typed values from env",
+			env: map[string]string{
+				"SHUTDOWN_TIMEOUT":     "10s",
+				"DB_PORT":              "6543",
+				"TRACING_SAMPLE_RATIO": "0.25",
+				"ARCHIVE_ENABLED":      "true",
+				"NOTIFY_SMTP_HOST":     "smtp.example.com",
+				"NOTIFY_SMTP_TO":       "a@example.com, b@example.com,",
+			},
+			want: func(c *Config) {
+				c.Server.ShutdownTimeout = 10 * time.Second
+				c.Database.Port = 6543
+				c.Tracing.SampleRatio = 0.25
+				c.Archive.Enabled = true
+				c.Notify.SMTPHost = "smtp.example.com"
+				c.Notify.SMTPTo = []string{"a@example.com", "b@example.com"}
+			},
+		},
+		{
+			name: "bool flag without value",
+			args: []string{"-server.reflection"},
+			want: func(c *Config) { c.Server.Reflection = true },
+		},
+		{
+			name: "explicit false flag overrides env",
+			env:  map[string]string{"ENABLE_GRPC_REFLECTION": "true"},
+			args: []string{"-server.reflection=false"},
+			want: func(c *Config) {},
+		},
+		{
+			name:     "arguments after the flags",
+			args:     []string{"-log.level=debug", "migrate", "up"},
+			want:     func(c *Config) { c.Log.Level = "debug" },
+			wantArgs: []string{"migrate", "up"},
+		},
+		{
+			name:  "password file replaces password",
+			files: map[string]string{"password": "from-file\n"},
+			env:   map[string]string{"DB_PASSWORD": "from-env", "DB_PASSWORD_FILE": "{password}"},
+			want: func(c *Config) {
+				c.Database.Password = "from-file"
+				c.Database.PasswordFile = "{password}"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+
+			paths := make(map[string]string, len(tt.files))
+			for name, content := range tt.files {
+				paths["{"+name+"}"] = writeFile(t, name, content)
+			}
+			expand := func(s string) string {
+				for placeholder, path := range paths {
+					s = strings.ReplaceAll(s, placeholder, path)
+				}
+				return s
+			}
+
+			for key, value := range tt.env {
+				t.Setenv(key, expand(value))
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = expand(arg)
+			}
+
+			cfg, rest, err := Load("test", args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			want := Default()
+			tt.want(want)
+			want.Database.PasswordFile = expand(want.Database.PasswordFile)
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("config =\n%s\nwant\n%s", cfg, want)
+			}
+			if len(rest) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(rest, tt.wantArgs) {
+					t.Errorf("remaining args = %q, want %q", rest, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		args  []string
+		err   string
+	}{
+		{
+			name:  "unknown yaml key",
+			files: map[string]string{"config.yaml": "log:\n  levle: debug\n"},
+			args:  []string{"-config", "{config.yaml}"},
+			err:   "field levle not found",
+		},
+		{
+			name:  "unknown toml key",
+			files: map[string]string{"config.toml": "[log]\nlevle = \"debug\"\n"},
+			args:  []string{"-config", "{config.toml}"},
+			err:   "unknown keys log.levle",
+		},
+		{
+			name:  "unknown file format",
+			files: map[string]string{"config.json": "{}"},
+			args:  []string{"-config", "{config.json}"},
+			err:   "unknown format",
+		},
+		{
+			name: "missing file",
+			args: []string{"-config", "/nonexistent/config.yaml"},
+			err:  "config file /nonexistent/config.yaml",
+		},
+		{
+			name: "invalid env value",
+			env:  map[string]string{"DB_PORT": "five"},
+			err:  "invalid DB_PORT",
+		},
+		{
+			name: "invalid flag value",
+			args: []string{"-server.shutdown_timeout", "soon"},
+			err:  "invalid -server.shutdown_timeout",
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-log.levle", "debug"},
+			err:  "flag provided but not defined",
+		},
+		{
+			name: "missing password file",
+			env:  map[string]string{"DB_PASSWORD_FILE": "/nonexistent/password"},
+			err:  "database.password_file",
+		},
+		{
+			name:  "invalid result",
+			files: map[string]string{"config.yaml": "log:\n  level: loud\n"},
+			args:  []string{"-config", "{config.yaml}"},
+			err:   "log.level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+
+			paths := make(map[string]string, len(tt.files))
+			for name, content := range tt.files {
+				paths["{"+name+"}"] = writeFile(t, name, content)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				if path, ok := paths[arg]; ok {
+					arg = path
+				}
+				args[i] = arg
+			}
+
+			_, _, err := Load("test", args)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("error = %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Server.AdminToken = "admin-token"
+	cfg.Database.Password = "db-password"
+	cfg.Archive.S3SecretKey = "s3-secret"
+	cfg.Database.User = "logsentinel"
+
+	redacted := cfg.Redacted()
+
+	for name, got := range map[string]string{
+		"server.admin_token":    redacted.Server.AdminToken,
+		"database.password":     redacted.Database.Password,
+		"archive.s3_secret_key": redacted.Archive.S3SecretKey,
+	} {
+		if got != "********" {
+			t.Errorf("%s = %q, want it masked", name, got)
+		}
+	}
+	if redacted.Archive.S3AccessKey != "" || redacted.Notify.SMTPPassword != "" {
+		t.Error("unset secrets were masked")
+	}
+	if redacted.Database.User != "logsentinel" {
+		t.Errorf("database.user = %q, settings that aren't secret must stay", redacted.Database.User)
+	}
+	if cfg.Server.AdminToken != "admin-token" || cfg.Database.Password != "db-password" {
+		t.Error("Redacted changed the original configuration")
+	}
+
+	out := cfg.String()
+	for _, secret := range []string{"admin-token", "db-password", "s3-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("String() contains the secret %q", secret)
+		}
+	}
+}
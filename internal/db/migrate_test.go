@@ -0,0 +1,133 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []Migration
+		// err is a substring of the expected error, empty when parsing
+		// succeeds
+		err string
+	}{
+		{
+			name:  "empty",
+			files: map[string]string{},
+			want:  []Migration{},
+		},
+		{
+			name: "pairs up and down",
+			files: map[string]string{
+				"0001_initial.up.sql":   "CREATE TABLE a ();",
+				"0001_initial.down.sql": "DROP TABLE a;",
+			},
+			want: []Migration{
+				{Version: 1, Name: "initial", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+			},
+		},
+		{
+			name: "ordered by numeric version",
+			files: map[string]string{
+				"0010_tenth.up.sql":  "10",
+				"0002_second.up.sql": "2",
+				"0001_first.up.sql":  "1",
+			},
+			want: []Migration{
+				{Version: 1, Name: "first", Up: "1"},
+				{Version: 2, Name: "second", Up: "2"},
+				{Version: 10, Name: "tenth", Up: "10"},
+			},
+		},
+		{
+			name:  "down step is optional",
+			files: map[string]string{"0003_job_run_slots.up.sql": "ALTER TABLE job_runs;"},
+			want:  []Migration{{Version: 3, Name: "job_run_slots", Up: "ALTER TABLE job_runs;"}},
+		},
+		{
+			name:  "up step is required",
+			files: map[string]string{"0001_initial.down.sql": "DROP TABLE a;"},
+			err:   "migration 1 has no up step",
+		},
+		{
+			name: "one name per version",
+			files: map[string]string{
+				"0001_initial.up.sql":   "CREATE TABLE a ();",
+				"0001_renamed.down.sql": "DROP TABLE a;",
+			},
+			err: "migration 1 has two names",
+		},
+		{
+			name:  "missing direction",
+			files: map[string]string{"0001_initial.sql": ""},
+			err:   `invalid migration file name "0001_initial.sql"`,
+		},
+		{
+			name:  "missing version",
+			files: map[string]string{"initial.up.sql": ""},
+			err:   "invalid migration file name",
+		},
+		{
+			name:  "dash separator",
+			files: map[string]string{"0001-initial.up.sql": ""},
+			err:   "invalid migration file name",
+		},
+		{
+			name:  "upper case direction",
+			files: map[string]string{"0001_initial.UP.sql": ""},
+			err:   "invalid migration file name",
+		},
+		{
+			name:  "stray file",
+			files: map[string]string{"0001_initial.up.sql": "", "README.md": ""},
+			err:   `invalid migration file name "README.md"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, body := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(body)}
+			}
+
+			got, err := parseMigrations(fsys)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("migrations = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if want := int64(i + 1); migration.Version != want {
+			t.Errorf("migration %d_%s has version %d, want %d without gaps", migration.Version, migration.Name, migration.Version, want)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has no down step", migration.Version, migration.Name)
+		}
+	}
+}
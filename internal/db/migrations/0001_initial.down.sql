@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS project_categories;
+DROP TABLE IF EXISTS processing_rules;
+DROP TABLE IF EXISTS redaction_counts;
+DROP TABLE IF EXISTS redaction_rules;
+DROP TABLE IF EXISTS anomalies;
+DROP TABLE IF EXISTS log_pattern_counts;
+DROP TABLE IF EXISTS log_patterns;
+DROP TABLE IF EXISTS log_rollups;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS alert_rules;
+DROP VIEW IF EXISTS searchable_logs;
+DROP TABLE IF EXISTS rehydrations;
+DROP TABLE IF EXISTS restored_logs;
+DROP TABLE IF EXISTS job_runs;
+DROP TABLE IF EXISTS project_retention_policies;
+DROP TABLE IF EXISTS plan_retention_policies;
+DROP TABLE IF EXISTS logs;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS account_type;
@@ -0,0 +1,272 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+DO $$ BEGIN
+    CREATE TYPE account_type AS ENUM ('free', 'pro');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+-- users table
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id VARCHAR(255) UNIQUE NOT NULL,
+    client_id VARCHAR(255) UNIQUE NOT NULL,
+    account_type account_type NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Projects table
+CREATE TABLE IF NOT EXISTS projects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    api_key VARCHAR(255) UNIQUE NOT NULL,
+    -- none, auto, json or logfmt, how ingested message bodies are parsed
+    body_format VARCHAR(10) NOT NULL DEFAULT 'none',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Logs table, range partitioned on created_at. The cron service creates the
+-- daily or weekly partitions ahead of time, logs_default only catches rows
+-- outside of them
+CREATE TABLE IF NOT EXISTS logs (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    pattern_id VARCHAR(16),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    trace_id VARCHAR(128),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+CREATE TABLE IF NOT EXISTS logs_default PARTITION OF logs DEFAULT;
+
+CREATE INDEX IF NOT EXISTS logs_created_at_idx ON logs(created_at);
+CREATE INDEX IF NOT EXISTS logs_project_id_idx ON logs(project_id);
+CREATE INDEX IF NOT EXISTS logs_project_id_pattern_id_idx ON logs(project_id, pattern_id, created_at);
+CREATE INDEX IF NOT EXISTS logs_project_id_trace_id_idx ON logs(project_id, trace_id) WHERE trace_id IS NOT NULL;
+
+-- Retention policies. retention_days = 0 keeps logs forever and an empty
+-- category applies to every category without a more specific rule
+CREATE TABLE IF NOT EXISTS plan_retention_policies (
+    account_type account_type NOT NULL,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    retention_days INT NOT NULL CHECK (retention_days >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_type, category)
+);
+
+-- Per project overrides, these replace the plan policy for the categories they cover
+CREATE TABLE IF NOT EXISTS project_retention_policies (
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    retention_days INT NOT NULL CHECK (retention_days >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, category)
+);
+
+INSERT INTO plan_retention_policies (account_type, category, retention_days) VALUES
+    ('free', '', 7),
+    ('pro', '', 0)
+ON CONFLICT DO NOTHING;
+
+-- Background job runs, resume_cursor lets a job that ran out of time
+-- continue where it stopped on its next run
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    rows_affected BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    resume_cursor TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS job_runs_job_name_started_at_idx ON job_runs(job_name, started_at DESC);
+
+-- Logs restored from the cold archive. Every rehydration gets its own list
+-- partition which is dropped once the rehydration expires
+CREATE TABLE IF NOT EXISTS restored_logs (
+    rehydration_id UUID NOT NULL,
+    id UUID NOT NULL,
+    project_id UUID NOT NULL,
+    category VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+) PARTITION BY LIST (rehydration_id);
+
+CREATE INDEX IF NOT EXISTS restored_logs_project_id_created_at_idx ON restored_logs(project_id, created_at);
+
+CREATE TABLE IF NOT EXISTS rehydrations (
+    id UUID PRIMARY KEY,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    range_start TIMESTAMP NOT NULL,
+    range_end TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    rows_restored BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Hot and restored logs together, restored rows stay here until their
+-- rehydration expires
+CREATE OR REPLACE VIEW searchable_logs AS
+    SELECT id, project_id, category, message, created_at, FALSE AS restored
+    FROM logs
+    UNION ALL
+    SELECT id, project_id, category, message, created_at, TRUE AS restored
+    FROM restored_logs;
+
+
+-- Alert rules. Threshold rules fire when more than threshold logs arrive
+-- within window_seconds, pattern rules fire on every matching log and
+-- resolve once no match was seen for window_seconds
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('threshold', 'pattern')),
+    category VARCHAR(64) NOT NULL DEFAULT '',
+    pattern TEXT NOT NULL DEFAULT '',
+    threshold BIGINT NOT NULL DEFAULT 0 CHECK (threshold >= 0),
+    window_seconds BIGINT NOT NULL CHECK (window_seconds > 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS alert_rules_project_id_idx ON alert_rules(project_id);
+
+-- Alert history, one row per firing period. The partial unique index keeps
+-- a single open alert per rule which is what deduplicates firing
+CREATE TABLE IF NOT EXISTS alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    state VARCHAR(20) NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    match_count BIGINT NOT NULL DEFAULT 1,
+    message TEXT,
+    fired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS alerts_open_rule_id_idx ON alerts(rule_id) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS alerts_project_id_fired_at_idx ON alerts(project_id, fired_at DESC);
+-- Usage rollups. Minute rows are maintained at ingestion, the compaction
+-- job folds them into hour and later day rows. Stats, quota checks and
+-- billing exports sum these instead of scanning logs
+CREATE TABLE IF NOT EXISTS log_rollups (
+    project_id UUID NOT NULL,
+    bucket TIMESTAMP NOT NULL,
+    category VARCHAR(64) NOT NULL,
+    granularity VARCHAR(10) NOT NULL DEFAULT 'minute' CHECK (granularity IN ('minute', 'hour', 'day')),
+    log_count BIGINT NOT NULL DEFAULT 0,
+    byte_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, bucket, category, granularity)
+);
+
+CREATE INDEX IF NOT EXISTS log_rollups_granularity_bucket_idx ON log_rollups(granularity, bucket);
+
+-- Message patterns found by clustering, template masks the variable tokens
+-- with <*>. Counts are per minute and pruned after the pattern retention,
+-- like the rollups neither references projects so a flush never fails on a
+-- deleted project
+CREATE TABLE IF NOT EXISTS log_patterns (
+    id VARCHAR(16) NOT NULL,
+    project_id UUID NOT NULL,
+    template TEXT NOT NULL,
+    first_seen TIMESTAMP NOT NULL,
+    last_seen TIMESTAMP NOT NULL,
+    PRIMARY KEY (project_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS log_pattern_counts (
+    project_id UUID NOT NULL,
+    pattern_id VARCHAR(16) NOT NULL,
+    bucket TIMESTAMP NOT NULL,
+    log_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, bucket, pattern_id)
+);
+
+CREATE INDEX IF NOT EXISTS log_pattern_counts_bucket_idx ON log_pattern_counts(bucket);
+
+-- Hours whose log volume deviated from the seasonal baseline of a project
+-- and category, score is positive for spikes and negative for drops
+CREATE TABLE IF NOT EXISTS anomalies (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    category VARCHAR(64) NOT NULL,
+    hour TIMESTAMP NOT NULL,
+    observed BIGINT NOT NULL,
+    baseline DOUBLE PRECISION NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('spike', 'drop')),
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, category, hour)
+);
+
+CREATE INDEX IF NOT EXISTS anomalies_project_id_hour_idx ON anomalies(project_id, hour DESC);
+
+-- Redaction rules. A rule with a built-in detector overrides that
+-- detector's action for the project, custom rules add their own pattern
+CREATE TABLE IF NOT EXISTS redaction_rules (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    detector VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    pattern TEXT NOT NULL DEFAULT '',
+    action VARCHAR(10) NOT NULL CHECK (action IN ('mask', 'hash', 'drop')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS redaction_rules_project_id_detector_idx ON redaction_rules(project_id, detector) WHERE detector <> 'custom';
+
+-- Values redacted per day, detector is the rule name for custom rules
+CREATE TABLE IF NOT EXISTS redaction_counts (
+    project_id UUID NOT NULL,
+    day DATE NOT NULL,
+    detector VARCHAR(255) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    redactions BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, day, detector, action)
+);
+
+-- Processing rules, run on every ingested log of the project in position
+-- order before redaction. Empty match values match every log
+CREATE TABLE IF NOT EXISTS processing_rules (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    match_category VARCHAR(64) NOT NULL DEFAULT '',
+    match_pattern TEXT NOT NULL DEFAULT '',
+    action VARCHAR(10) NOT NULL CHECK (action IN ('drop', 'sample', 'rewrite', 'extract', 'rename', 'tag')),
+    sample_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    pattern TEXT NOT NULL DEFAULT '',
+    replacement TEXT NOT NULL DEFAULT '',
+    target VARCHAR(64) NOT NULL DEFAULT '',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS processing_rules_project_id_position_idx ON processing_rules(project_id, position);
+
+-- Categories logs of a project can use, projects without rows here use the
+-- default categories
+CREATE TABLE IF NOT EXISTS project_categories (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, name)
+);
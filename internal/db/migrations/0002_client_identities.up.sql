@@ -0,0 +1,10 @@
+-- Client certificate identities of a project. Clients presenting a verified
+-- certificate with one of them over mutual TLS can send logs to the project
+-- without an API key, an identity belongs to one project at most
+CREATE TABLE IF NOT EXISTS project_client_identities (
+    identity VARCHAR(255) PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS project_client_identities_project_id_idx ON project_client_identities(project_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS job_runs_job_name_scheduled_at_idx;
+
+ALTER TABLE job_runs DROP COLUMN IF EXISTS scheduled_at;
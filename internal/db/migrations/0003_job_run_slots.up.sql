@@ -0,0 +1,6 @@
+-- The scheduled time a run was started for. A slot runs once across all
+-- replicas, whichever replica records it first runs it, manual triggers
+-- have no slot
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS job_runs_job_name_scheduled_at_idx ON job_runs(job_name, scheduled_at) WHERE scheduled_at IS NOT NULL;
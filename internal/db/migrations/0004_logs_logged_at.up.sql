@@ -0,0 +1,4 @@
+-- The time a log happened according to the client, lifted from a parsed
+-- body. created_at stays the time the server ingested the log, partitions,
+-- retention and exports go by it
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS logged_at TIMESTAMP;
@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS searchable_logs;
+
+CREATE VIEW searchable_logs AS
+    SELECT id, project_id, category, message, created_at, FALSE AS restored
+    FROM logs
+    UNION ALL
+    SELECT id, project_id, category, message, created_at, TRUE AS restored
+    FROM restored_logs;
+
+ALTER TABLE restored_logs
+    DROP COLUMN IF EXISTS logged_at,
+    DROP COLUMN IF EXISTS trace_id,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS pattern_id,
+    DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,18 @@
+-- Restored logs keep the structured fields of the archived logs so searches
+-- and exports of a rehydrated range match those of the live logs
+ALTER TABLE restored_logs
+    ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS pattern_id VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS trace_id VARCHAR(128),
+    ADD COLUMN IF NOT EXISTS logged_at TIMESTAMP;
+
+-- new view columns can only be added after the existing ones
+CREATE OR REPLACE VIEW searchable_logs AS
+    SELECT id, project_id, category, message, created_at, FALSE AS restored,
+        metadata, pattern_id, tags, trace_id, logged_at
+    FROM logs
+    UNION ALL
+    SELECT id, project_id, category, message, created_at, TRUE AS restored,
+        metadata, pattern_id, tags, trace_id, logged_at
+    FROM restored_logs;
@@ -0,0 +1,194 @@
+package export
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// Encoder writes log records in an export format. Flush pushes everything
+// written so far to the underlying writer so output always ends on a record
+// boundary, Close finishes the file.
+type Encoder interface {
+	Write(records []archive.Record) error
+	Flush() error
+	Close() error
+}
+
+// NewEncoder creates an encoder for format writing to w, compressed with
+// compression. The output is deterministic for the same records which is
+// what makes resuming an export from a byte offset possible.
+func NewEncoder(format, compression string, w io.Writer) (Encoder, error) {
+	compressor, err := newCompressor(compression, w)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatNDJSON, "":
+		return &ndjsonEncoder{compressor: compressor, enc: json.NewEncoder(compressor)}, nil
+	case FormatCSV:
+		e := &csvEncoder{compressor: compressor, w: csv.NewWriter(compressor)}
+		if err := e.w.Write([]string{"id", "project_id", "category", "message", "created_at", "logged_at", "trace_id", "pattern_id", "tags", "metadata"}); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case FormatParquet:
+		return &parquetEncoder{compressor: compressor, w: parquet.NewGenericWriter[archive.Record](compressor)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// compressor is the compression stage below an encoder.
+type compressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+type nopCompressor struct {
+	io.Writer
+}
+
+func (nopCompressor) Flush() error { return nil }
+func (nopCompressor) Close() error { return nil }
+
+func newCompressor(compression string, w io.Writer) (compressor, error) {
+	switch compression {
+	case CompressionNone, "":
+		return nopCompressor{w}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		// a single encoder goroutine keeps the output deterministic
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, fmt.Errorf("unknown export compression %q", compression)
+	}
+}
+
+type ndjsonEncoder struct {
+	compressor compressor
+	enc        *json.Encoder
+}
+
+func (e *ndjsonEncoder) Write(records []archive.Record) error {
+	for _, record := range records {
+		if err := e.enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ndjsonEncoder) Flush() error { return e.compressor.Flush() }
+func (e *ndjsonEncoder) Close() error { return e.compressor.Close() }
+
+type csvEncoder struct {
+	compressor compressor
+	w          *csv.Writer
+}
+
+// Write puts tags and metadata in one column each as JSON, empty when the
+// log has none.
+func (e *csvEncoder) Write(records []archive.Record) error {
+	for _, record := range records {
+		var loggedAt string
+		if record.LoggedAt != nil {
+			loggedAt = record.LoggedAt.Format(time.RFC3339Nano)
+		}
+		tags, err := csvJSON(len(record.Tags), record.Tags)
+		if err != nil {
+			return err
+		}
+		metadata, err := csvJSON(len(record.Metadata), record.Metadata)
+		if err != nil {
+			return err
+		}
+
+		err = e.w.Write([]string{
+			record.ID,
+			record.ProjectID,
+			record.Category,
+			record.Message,
+			record.CreatedAt.Format(time.RFC3339Nano),
+			loggedAt,
+			record.TraceID,
+			record.PatternID,
+			tags,
+			metadata,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func csvJSON(n int, v any) (string, error) {
+	if n == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.compressor.Flush()
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	return e.compressor.Close()
+}
+
+// parquetEncoder writes one row group per Flush so memory stays bounded by
+// the size of a fetch batch.
+type parquetEncoder struct {
+	compressor compressor
+	w          *parquet.GenericWriter[archive.Record]
+}
+
+func (e *parquetEncoder) Write(records []archive.Record) error {
+	_, err := e.w.Write(records)
+	return err
+}
+
+func (e *parquetEncoder) Flush() error {
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	return e.compressor.Flush()
+}
+
+func (e *parquetEncoder) Close() error {
+	if err := e.w.Close(); err != nil {
+		return err
+	}
+	return e.compressor.Close()
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+)
+
+func testRecords() []archive.Record {
+	createdAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	loggedAt := createdAt.Add(-time.Second)
+	return []archive.Record{
+		{
+			ID:        "1",
+			ProjectID: "p",
+			Category:  "error",
+			Message:   "payment failed",
+			CreatedAt: createdAt,
+			Metadata:  map[string]string{"user": "u1", "order": "42", "amount": "10", "currency": "EUR"},
+			Tags:      []string{"billing", "retry"},
+			TraceID:   "trace",
+			PatternID: "pattern",
+			LoggedAt:  &loggedAt,
+		},
+		{ID: "2", ProjectID: "p", Category: "info", Message: "cache warmed up", CreatedAt: createdAt},
+	}
+}
+
+func TestCSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	encoder, err := NewEncoder(FormatCSV, CompressionNone, &buf)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	if err := encoder.Write(testRecords()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading csv: %v", err)
+	}
+	want := [][]string{
+		{"id", "project_id", "category", "message", "created_at", "logged_at", "trace_id", "pattern_id", "tags", "metadata"},
+		{"1", "p", "error", "payment failed", "2026-03-01T12:30:00Z", "2026-03-01T12:29:59Z", "trace", "pattern", `["billing","retry"]`, `{"amount":"10","currency":"EUR","order":"42","user":"u1"}`},
+		{"2", "p", "info", "cache warmed up", "2026-03-01T12:30:00Z", "", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%q\nwant\n%q", rows, want)
+	}
+}
+
+// TestDeterministic encodes the same records repeatedly, resuming an export
+// from a byte offset relies on identical output.
+func TestDeterministic(t *testing.T) {
+	for _, format := range []string{FormatNDJSON, FormatCSV, FormatParquet} {
+		for _, compression := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+			t.Run(format+"/"+compression, func(t *testing.T) {
+				var first []byte
+				for i := 0; i < 10; i++ {
+					var buf bytes.Buffer
+					encoder, err := NewEncoder(format, compression, &buf)
+					if err != nil {
+						t.Fatalf("NewEncoder: %v", err)
+					}
+					if err := encoder.Write(testRecords()); err != nil {
+						t.Fatalf("Write: %v", err)
+					}
+					if err := encoder.Close(); err != nil {
+						t.Fatalf("Close: %v", err)
+					}
+
+					if first == nil {
+						first = buf.Bytes()
+					} else if !bytes.Equal(buf.Bytes(), first) {
+						t.Fatalf("encoding %d differs from the first", i+1)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/AjayShukla007/logsentinel/internal/db"
+)
+
+const (
+	defaultCheckInterval = 10 * time.Second
+	// checkTimeout bounds a single check so a hanging database can't stall
+	// the others
+	checkTimeout = 5 * time.Second
+	// backlogThreshold is the share of a queue above which /readyz reports
+	// its backlog
+	backlogThreshold = 0.9
+)
+
+// Check returns why the server can't take traffic, or nil.
+type Check func(ctx context.Context) error
+
+// Checker runs the readiness checks periodically and publishes their outcome
+// through the grpc.health.v1 service and the HTTP /readyz endpoint. A gRPC
+// service is serving while the checks it depends on pass, the overall ""
+// service while every check passes. Info checks are only shown on /readyz.
+type Checker struct {
+	server   *health.Server
+	interval time.Duration
+
+	checks   map[string]Check
+	info     map[string]bool
+	services map[string][]string
+
+	mu           sync.RWMutex
+	results      map[string]error
+	shuttingDown bool
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+func NewChecker(server *health.Server) *Checker {
+	return &Checker{
+		server:   server,
+		interval: defaultCheckInterval,
+		checks:   make(map[string]Check),
+		info:     make(map[string]bool),
+		services: make(map[string][]string),
+		results:  make(map[string]error),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// AddCheck registers a readiness check, it must be called before Start.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.checks[name] = check
+}
+
+// AddInfo registers a check reported on /readyz that never makes the server
+// unready, for conditions degrading best effort work only. It must be called
+// before Start.
+func (c *Checker) AddInfo(name string, check Check) {
+	c.checks[name] = check
+	c.info[name] = true
+}
+
+// AddService reports the gRPC service as serving while the named checks
+// pass, it must be called before Start.
+func (c *Checker) AddService(service string, checks ...string) {
+	c.services[service] = checks
+}
+
+// Start runs the checks once, so statuses are set before the server takes
+// traffic, and then periodically.
+func (c *Checker) Start() {
+	c.run(context.Background())
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.run(context.Background())
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic checks.
+func (c *Checker) Stop(ctx context.Context) error {
+	close(c.quit)
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown reports every service as not serving for good, it is called when
+// shutdown starts so orchestrators stop routing traffic to the server.
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	c.shuttingDown = true
+	c.mu.Unlock()
+	c.server.Shutdown()
+}
+
+func (c *Checker) run(ctx context.Context) {
+	results := make(map[string]error, len(c.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			err := check(checkCtx)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, err := range results {
+		kind := "Readiness"
+		if c.info[name] {
+			kind = "Info"
+		}
+		if prev := c.results[name]; err != nil && (prev == nil || prev.Error() != err.Error()) {
+			slog.Warn(kind+" check failing", "check", name, "err", err)
+		} else if err == nil && prev != nil {
+			slog.Info(kind+" check recovered", "check", name)
+		}
+	}
+	c.results = results
+	if c.shuttingDown {
+		return
+	}
+
+	all := make([]string, 0, len(results))
+	for name := range results {
+		if !c.info[name] {
+			all = append(all, name)
+		}
+	}
+	c.server.SetServingStatus("", status(results, all))
+	for service, checks := range c.services {
+		c.server.SetServingStatus(service, status(results, checks))
+	}
+}
+
+// status is serving when the named checks passed.
+func status(results map[string]error, names []string) healthpb.HealthCheckResponse_ServingStatus {
+	for _, name := range names {
+		if results[name] != nil {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+type report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+	Info   map[string]string `json:"info,omitempty"`
+}
+
+// LiveHandler serves /healthz, it answers as long as the process does.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, http.StatusOK, report{Status: "ok"})
+	})
+}
+
+// ReadyHandler serves /readyz with the outcome of the last checks, it
+// answers 503 while a check other than an info check fails or the server
+// shuts down.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		rep := report{Status: "ready", Checks: make(map[string]string, len(c.results))}
+		code := http.StatusOK
+		for name, err := range c.results {
+			if c.info[name] {
+				if rep.Info == nil {
+					rep.Info = make(map[string]string)
+				}
+				rep.Info[name] = "ok"
+				if err != nil {
+					rep.Info[name] = err.Error()
+				}
+				continue
+			}
+			rep.Checks[name] = "ok"
+			if err != nil {
+				rep.Checks[name] = err.Error()
+				rep.Status = "not ready"
+				code = http.StatusServiceUnavailable
+			}
+		}
+		if c.shuttingDown {
+			rep.Status = "shutting down"
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, rep)
+	})
+}
+
+func writeReport(w http.ResponseWriter, code int, rep report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(rep)
+}
+
+// DatabaseCheck fails while the database doesn't answer.
+func DatabaseCheck(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) error {
+		return pool.Ping(ctx)
+	}
+}
+
+// MigrationCheck fails while embedded migrations aren't applied.
+func MigrationCheck(migrator *db.Migrator) Check {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("checking migrations: %w", err)
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	}
+}
+
+// Queue is a best effort in-memory queue, it drops work once full.
+type Queue interface {
+	Backlog() (queued, capacity int)
+}
+
+// BacklogCheck fails while one of the named queues is nearly full. Full
+// queues drop work instead of slowing the server down, register it with
+// AddInfo.
+func BacklogCheck(queues map[string]Queue) Check {
+	names := make([]string, 0, len(queues))
+	for name := range queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(ctx context.Context) error {
+		for _, name := range names {
+			queued, capacity := queues[name].Backlog()
+			if capacity > 0 && float64(queued) >= backlogThreshold*float64(capacity) {
+				return fmt.Errorf("%s queue backlog at %d of %d", name, queued, capacity)
+			}
+		}
+		return nil
+	}
+}
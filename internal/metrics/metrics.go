@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics of the server, registered with the default Prometheus registry
+// which also exports Go runtime and process metrics.
+var (
+	GRPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "logsentinel_grpc_requests_total",
+		Help: "gRPC requests handled, by service, method and status code.",
+	}, []string{"service", "method", "code"})
+
+	GRPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "logsentinel_grpc_request_duration_seconds",
+		Help:    "Time spent handling gRPC requests, streams count until they close.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"service", "method"})
+
+	LogsIngested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "logsentinel_logs_ingested_total",
+		Help: "Logs stored, by project and category.",
+	}, []string{"project", "category"})
+
+	LogsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "logsentinel_logs_dropped_total",
+		Help: "Logs dropped at ingestion, by project and the kind of rule that dropped them.",
+	}, []string{"project", "reason"})
+
+	RateLimited = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "logsentinel_rate_limit_rejections_total",
+		Help: "Logs rejected because their client exceeded its rate limit.",
+	})
+
+	ConnectSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "logsentinel_connect_sessions",
+		Help: "Authenticated ConnectClient sessions.",
+	})
+
+	StreamSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "logsentinel_stream_subscribers",
+		Help: "Clients following StreamLogs.",
+	})
+
+	JobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "logsentinel_job_runs_total",
+		Help: "Scheduled job runs, by job and outcome. Runs left to another replica count as skipped.",
+	}, []string{"job", "status"})
+
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "logsentinel_job_duration_seconds",
+		Help:    "Duration of scheduled job runs.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+	}, []string{"job"})
+)
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports the statistics of a database connection pool,
+// they are read from the pool on every scrape.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns       *prometheus.Desc
+	idleConns           *prometheus.Desc
+	totalConns          *prometheus.Desc
+	maxConns            *prometheus.Desc
+	acquires            *prometheus.Desc
+	emptyAcquires       *prometheus.Desc
+	canceledAcquires    *prometheus.Desc
+	acquireDuration     *prometheus.Desc
+	newConns            *prometheus.Desc
+	maxLifetimeDestroys *prometheus.Desc
+	maxIdleTimeDestroys *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("logsentinel_db_pool_"+name, help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool:                pool,
+		acquiredConns:       desc("acquired_connections", "Connections currently in use."),
+		idleConns:           desc("idle_connections", "Idle connections in the pool."),
+		totalConns:          desc("connections", "Open connections, in use, idle or being established."),
+		maxConns:            desc("max_connections", "Maximum size of the pool."),
+		acquires:            desc("acquires_total", "Connections acquired from the pool."),
+		emptyAcquires:       desc("empty_acquires_total", "Acquires that waited for a connection because none was idle."),
+		canceledAcquires:    desc("canceled_acquires_total", "Acquires canceled by their context."),
+		acquireDuration:     desc("acquire_duration_seconds_total", "Time spent waiting for connections."),
+		newConns:            desc("new_connections_total", "Connections opened."),
+		maxLifetimeDestroys: desc("max_lifetime_destroys_total", "Connections closed for exceeding their maximum lifetime."),
+		maxIdleTimeDestroys: desc("max_idle_destroys_total", "Connections closed for being idle too long."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+	ch <- c.newConns
+	ch <- c.maxLifetimeDestroys
+	ch <- c.maxIdleTimeDestroys
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+
+	gauge(c.acquiredConns, float64(stat.AcquiredConns()))
+	gauge(c.idleConns, float64(stat.IdleConns()))
+	gauge(c.totalConns, float64(stat.TotalConns()))
+	gauge(c.maxConns, float64(stat.MaxConns()))
+	counter(c.acquires, float64(stat.AcquireCount()))
+	counter(c.emptyAcquires, float64(stat.EmptyAcquireCount()))
+	counter(c.canceledAcquires, float64(stat.CanceledAcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.newConns, float64(stat.NewConnsCount()))
+	counter(c.maxLifetimeDestroys, float64(stat.MaxLifetimeDestroyCount()))
+	counter(c.maxIdleTimeDestroys, float64(stat.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,318 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// attemptTimeout bounds a single delivery attempt
+const attemptTimeout = 10 * time.Second
+
+var ErrChannelNotFound = errors.New("notification channel not found")
+
+type Config struct {
+	// MaxAttempts is the number of delivery attempts per event and channel
+	MaxAttempts int
+	// InitialBackoff doubles after every failed attempt up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RatePerMinute and Burst bound the events a single channel delivers,
+	// anything above is dropped so an alert storm can't flood a channel
+	RatePerMinute int
+	Burst         int
+	// Cooldown suppresses repeats of the same event within the period
+	Cooldown time.Duration
+	// QueueSize bounds the events waiting per channel
+	QueueSize int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		RatePerMinute:  10,
+		Burst:          20,
+		Cooldown:       5 * time.Minute,
+		QueueSize:      256,
+	}
+}
+
+// Dispatcher fans events out to every channel. Notify never blocks, each
+// channel delivers from its own queue with retries so a slow or failing
+// channel doesn't hold up the others or the caller.
+type Dispatcher struct {
+	templates *Templates
+	config    Config
+	channels  []*channel
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+type channel struct {
+	notifier Notifier
+	queue    chan Event
+	limiter  *tokenBucket
+}
+
+func NewDispatcher(templates *Templates, config Config, notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{
+		templates: templates,
+		config:    config,
+		lastSent:  make(map[string]time.Time),
+		quit:      make(chan struct{}),
+	}
+
+	for _, notifier := range notifiers {
+		c := &channel{
+			notifier: notifier,
+			queue:    make(chan Event, config.QueueSize),
+			limiter:  newTokenBucket(config.RatePerMinute, config.Burst),
+		}
+		d.channels = append(d.channels, c)
+
+		d.wg.Add(1)
+		go d.deliverLoop(c)
+	}
+
+	return d
+}
+
+// Channels returns the names of the configured channels.
+func (d *Dispatcher) Channels() []string {
+	names := make([]string, 0, len(d.channels))
+	for _, c := range d.channels {
+		names = append(names, c.notifier.Name())
+	}
+	return names
+}
+
+// Backlog returns how many events wait for delivery over all channels and
+// how many their queues hold.
+func (d *Dispatcher) Backlog() (int, int) {
+	queued, capacity := 0, 0
+	for _, c := range d.channels {
+		queued += len(c.queue)
+		capacity += cap(c.queue)
+	}
+	return queued, capacity
+}
+
+// Notify queues an event on every channel.
+func (d *Dispatcher) Notify(event Event) {
+	if len(d.channels) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	if d.suppressed(event) {
+		return
+	}
+
+	for _, c := range d.channels {
+		select {
+		case c.queue <- event:
+		default:
+			slog.Warn("Notification queue full, dropping event", "channel", c.notifier.Name(), "kind", event.Kind)
+		}
+	}
+}
+
+// suppressed reports whether the same event was already sent within the
+// cooldown, resolutions are never suppressed so state changes get through.
+func (d *Dispatcher) suppressed(event Event) bool {
+	if d.config.Cooldown <= 0 || event.Kind == KindAlertResolved {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := event.key()
+	if last, ok := d.lastSent[key]; ok && event.Time.Sub(last) < d.config.Cooldown {
+		return true
+	}
+	d.lastSent[key] = event.Time
+
+	for k, last := range d.lastSent {
+		if event.Time.Sub(last) >= d.config.Cooldown {
+			delete(d.lastSent, k)
+		}
+	}
+	return false
+}
+
+// Test sends an event to one channel, or every channel for an empty name,
+// right away with a single attempt and returns the outcome per channel.
+func (d *Dispatcher) Test(ctx context.Context, name string, event Event) (map[string]error, error) {
+	msg, err := d.templates.Render(event)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]error)
+	for _, c := range d.channels {
+		if name != "" && c.notifier.Name() != name {
+			continue
+		}
+		results[c.notifier.Name()] = c.notifier.Send(ctx, event, msg)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrChannelNotFound
+	}
+	return results, nil
+}
+
+// Stop delivers what is already queued and stops the channels.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	close(d.quit)
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) deliverLoop(c *channel) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case event := <-c.queue:
+			d.deliver(c, event)
+		case <-d.quit:
+			for {
+				select {
+				case event := <-c.queue:
+					d.deliver(c, event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends an event with retries. Once the dispatcher is stopping every
+// event still gets its current attempt but no further retries.
+func (d *Dispatcher) deliver(c *channel, event Event) {
+	name := c.notifier.Name()
+	if !c.limiter.allow() {
+		slog.Warn("Notification channel is rate limited, dropping event", "channel", name, "kind", event.Kind)
+		return
+	}
+
+	msg, err := d.templates.Render(event)
+	if err != nil {
+		slog.Error("Error rendering notification", "kind", event.Kind, "err", err)
+		return
+	}
+
+	backoff := d.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), attemptTimeout)
+		err = c.notifier.Send(ctx, event, msg)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if isPermanent(err) || attempt >= d.config.MaxAttempts {
+			slog.Error("Giving up on notification", "kind", event.Kind, "channel", name, "attempts", attempt, "err", err)
+			return
+		}
+
+		// full jitter keeps replicas from retrying in lockstep
+		wait := time.Duration(rand.Int63n(int64(backoff) + 1))
+		select {
+		case <-time.After(wait):
+		case <-d.quit:
+			slog.Warn("Dropping notification on shutdown", "kind", event.Kind, "channel", name, "err", err)
+			return
+		}
+
+		backoff *= 2
+		if backoff > d.config.MaxBackoff {
+			backoff = d.config.MaxBackoff
+		}
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// permanent marks errors retrying can't fix.
+func permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// tokenBucket refills ratePerMinute tokens per minute up to burst.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	burst    float64
+	rate     float64
+	lastFill time.Time
+}
+
+func newTokenBucket(ratePerMinute, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		tokens:   float64(burst),
+		burst:    float64(burst),
+		rate:     float64(ratePerMinute) / float64(time.Minute),
+		lastFill: time.Now(),
+	}
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += float64(now.Sub(b.lastFill)) * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
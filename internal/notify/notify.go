@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	KindRateLimited   = "rate_limit_exceeded"
+	KindQuotaExceeded = "quota_exceeded"
+	KindAlertFiring   = "alert_firing"
+	KindAlertResolved = "alert_resolved"
+	KindAnomaly       = "anomaly_detected"
+	KindTest          = "test"
+
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Event is something worth telling an operator about.
+type Event struct {
+	Kind      string            `json:"kind"`
+	Severity  string            `json:"severity"`
+	Title     string            `json:"title"`
+	ProjectID string            `json:"project_id,omitempty"`
+	ClientID  string            `json:"client_id,omitempty"`
+	UserID    string            `json:"user_id,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// key identifies repeats of the same event for storm suppression.
+func (e Event) key() string {
+	return e.Kind + "/" + e.ProjectID + "/" + e.ClientID + "/" + e.UserID + "/" + e.Title
+}
+
+// Message is an event rendered through the templates.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notifier delivers a rendered event to one channel. Send makes a single
+// attempt, retries are handled by the dispatcher.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, event Event, msg Message) error
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPNotifier emails events. Authentication is only attempted when a
+// username is set, net/smtp refuses plain auth over unencrypted connections
+// to hosts other than localhost.
+type SMTPNotifier struct {
+	name   string
+	config SMTPConfig
+}
+
+func NewSMTPNotifier(name string, config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{name: name, config: config}
+}
+
+func (s *SMTPNotifier) Name() string {
+	return s.name
+}
+
+func (s *SMTPNotifier) Send(ctx context.Context, event Event, msg Message) error {
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	addr := net.JoinHostPort(s.config.Host, fmt.Sprint(s.config.Port))
+
+	// smtp.SendMail has no context, run it aside so a hung server can't
+	// outlive the attempt
+	errc := make(chan error, 1)
+	go func() {
+		errc <- smtp.SendMail(addr, auth, s.config.From, s.config.To, s.compose(msg))
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *SMTPNotifier) compose(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", sanitizeHeader(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// sanitizeHeader keeps templated subjects from injecting headers.
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+const (
+	defaultSubject = `[LogSentinel] {{.Severity}}: {{.Title}}`
+	defaultBody    = `{{.Title}}
+{{if .ProjectID}}Project: {{.ProjectID}}
+{{end}}{{if .ClientID}}Client: {{.ClientID}}
+{{end}}{{if .UserID}}User: {{.UserID}}
+{{end}}{{range $k, $v := .Fields}}{{$k}}: {{$v}}
+{{end}}Time: {{.Time.Format "2006-01-02T15:04:05Z07:00"}}
+`
+)
+
+// Templates renders events into messages. Every kind uses the default
+// templates unless a template directory overrides them with
+// <kind>.subject.tmpl or <kind>.body.tmpl.
+type Templates struct {
+	subjects map[string]*template.Template
+	bodies   map[string]*template.Template
+}
+
+// LoadTemplates parses the default templates and the overrides in dir, an
+// empty dir only uses the defaults.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{
+		subjects: make(map[string]*template.Template),
+		bodies:   make(map[string]*template.Template),
+	}
+
+	var err error
+	if t.subjects[""], err = template.New("subject").Parse(defaultSubject); err != nil {
+		return nil, err
+	}
+	if t.bodies[""], err = template.New("body").Parse(defaultBody); err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return t, nil
+	}
+
+	kinds := []string{KindRateLimited, KindQuotaExceeded, KindAlertFiring, KindAlertResolved, KindAnomaly, KindTest}
+	for _, kind := range kinds {
+		if err := loadOverride(t.subjects, dir, kind, "subject"); err != nil {
+			return nil, err
+		}
+		if err := loadOverride(t.bodies, dir, kind, "body"); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func loadOverride(templates map[string]*template.Template, dir, kind, part string) error {
+	name := fmt.Sprintf("%s.%s.tmpl", kind, part)
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(name).Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	templates[kind] = tmpl
+	return nil
+}
+
+func (t *Templates) Render(event Event) (Message, error) {
+	subject, err := execute(t.pick(t.subjects, event.Kind), event)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := execute(t.pick(t.bodies, event.Kind), event)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: subject, Body: body}, nil
+}
+
+func (t *Templates) pick(templates map[string]*template.Template, kind string) *template.Template {
+	if tmpl, ok := templates[kind]; ok {
+		return tmpl
+	}
+	return templates[""]
+}
+
+func execute(tmpl *template.Template, event Event) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("rendering %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	signatureHeader = "X-LogSentinel-Signature"
+	timestampHeader = "X-LogSentinel-Timestamp"
+)
+
+// WebhookNotifier posts events as JSON. When a secret is set every request
+// carries an HMAC-SHA256 over "<timestamp>.<body>" so receivers can verify
+// the sender and reject replays.
+type WebhookNotifier struct {
+	name   string
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookNotifier(name, url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		name:   name,
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+func (w *WebhookNotifier) Send(ctx context.Context, event Event, msg Message) error {
+	payload, err := json.Marshal(struct {
+		Event   Event  `json:"event"`
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}{event, msg.Subject, msg.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(timestampHeader, timestamp)
+		req.Header.Set(signatureHeader, "sha256="+Sign(w.secret, timestamp, payload))
+	}
+
+	return post(w.client, req)
+}
+
+// Sign computes the webhook signature, exported so receivers written in Go
+// can verify requests.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SlackNotifier posts to Slack style incoming webhooks, which most chat
+// tools accept.
+type SlackNotifier struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func NewSlackNotifier(name, url string) *SlackNotifier {
+	return &SlackNotifier{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SlackNotifier) Name() string {
+	return s.name
+}
+
+func (s *SlackNotifier) Send(ctx context.Context, event Event, msg Message) error {
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Body),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return post(s.client, req)
+}
+
+// post sends the request and turns non 2xx responses into errors, 4xx
+// other than 429 won't get better with retries.
+func post(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("%s responded with %s", req.URL.Host, resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return permanent(err)
+	}
+	return err
+}
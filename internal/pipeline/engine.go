@@ -0,0 +1,303 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/category"
+	"github.com/AjayShukla007/logsentinel/internal/reload"
+	"github.com/AjayShukla007/logsentinel/internal/repository/processing"
+)
+
+// Log is a log as it passes through the rules. Time and TraceID are only
+// set when parsing the body found them, Time is stored as logged_at next to
+// the ingest time.
+type Log struct {
+	Category string
+	Message  string
+	Metadata map[string]string
+	Tags     []string
+	Time     time.Time
+	TraceID  string
+}
+
+// Step records what a rule did to a log, for dry runs. Parsing the body is
+// recorded as a step without a rule.
+type Step struct {
+	Rule    *processing.Rule
+	Matched bool
+	Note    string
+}
+
+// Outcome is a log after the rules of its project ran. A dropped log keeps
+// its state at the rule that dropped it.
+type Outcome struct {
+	Log       Log
+	Dropped   bool
+	DroppedBy string
+	Steps     []Step
+}
+
+type compiledRule struct {
+	rule    *processing.Rule
+	match   *regexp.Regexp
+	pattern *regexp.Regexp
+}
+
+// Validate reports why a rule can't be used, if it can't.
+func Validate(rule *processing.Rule) error {
+	_, err := compile(rule)
+	return err
+}
+
+// compile checks a rule and prepares its patterns.
+func compile(rule *processing.Rule) (*compiledRule, error) {
+	c := &compiledRule{rule: rule}
+
+	var err error
+	if rule.MatchPattern != "" {
+		if c.match, err = regexp.Compile(rule.MatchPattern); err != nil {
+			return nil, fmt.Errorf("invalid match_pattern: %w", err)
+		}
+	}
+
+	switch rule.Action {
+	case processing.ActionDrop:
+	case processing.ActionSample:
+		if rule.SamplePercent < 0 || rule.SamplePercent > 100 {
+			return nil, fmt.Errorf("sample_percent must be between 0 and 100")
+		}
+	case processing.ActionRewrite, processing.ActionExtract:
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("pattern is required for %s rules", rule.Action)
+		}
+		if c.pattern, err = compileGrok(rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	case processing.ActionRename:
+		if rule.Target == "" {
+			return nil, fmt.Errorf("target is required for rename rules")
+		}
+	case processing.ActionTag:
+		if len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("tags are required for tag rules")
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %q", rule.Action)
+	}
+	return c, nil
+}
+
+// Engine parses the bodies and runs the processing rules of each project on
+// ingested logs. Rules and body formats are cached per project and reloaded
+// periodically.
+type Engine struct {
+	repo       processing.Repository
+	categories *category.Registry
+
+	mu      sync.RWMutex
+	rules   map[uuid.UUID][]*compiledRule
+	formats map[uuid.UUID]string
+
+	reloads *reload.Loop
+}
+
+func NewEngine(repo processing.Repository, categories *category.Registry) *Engine {
+	e := &Engine{
+		repo:       repo,
+		categories: categories,
+		rules:      make(map[uuid.UUID][]*compiledRule),
+		formats:    make(map[uuid.UUID]string),
+	}
+	e.reloads = reload.New("processing rules", e.Reload)
+	return e
+}
+
+// Start loads the rules and reloads them periodically until Stop is called.
+func (e *Engine) Start(ctx context.Context) error {
+	return e.reloads.Start(ctx)
+}
+
+func (e *Engine) Stop(ctx context.Context) error {
+	return e.reloads.Stop(ctx)
+}
+
+// Reload replaces the cached rules with the enabled rules in the database
+// and the cached body formats with the formats of the projects.
+func (e *Engine) Reload(ctx context.Context) error {
+	rules, err := e.repo.ListAllRules(ctx)
+	if err != nil {
+		return fmt.Errorf("loading processing rules: %w", err)
+	}
+	formats, err := e.repo.ListBodyFormats(ctx)
+	if err != nil {
+		return fmt.Errorf("loading body formats: %w", err)
+	}
+
+	byProject := make(map[uuid.UUID][]*compiledRule)
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		compiled, err := compile(rule)
+		if err != nil {
+			slog.Warn("Skipping processing rule", "rule", rule.ID, "err", err)
+			continue
+		}
+		byProject[rule.ProjectID] = append(byProject[rule.ProjectID], compiled)
+	}
+
+	e.mu.Lock()
+	e.rules = byProject
+	e.formats = formats
+	e.mu.Unlock()
+	return nil
+}
+
+// Process parses the body of a log in the format of its project, then runs
+// the cached rules of the project on it.
+func (e *Engine) Process(projectID uuid.UUID, l Log) Outcome {
+	e.mu.RLock()
+	rules, format := e.rules[projectID], e.formats[projectID]
+	e.mu.RUnlock()
+
+	return run(format, rules, l, e.allowed(projectID), false)
+}
+
+// DryRun parses the body of a log in the given format and runs the given
+// rules on it, recording every step. Sample rules never drop in a dry run,
+// their step notes the chance instead.
+func (e *Engine) DryRun(projectID uuid.UUID, format string, rules []*processing.Rule, l Log) (Outcome, error) {
+	var compiled []*compiledRule
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		c, err := compile(rule)
+		if err != nil {
+			return Outcome{}, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		compiled = append(compiled, c)
+	}
+	return run(format, compiled, l, e.allowed(projectID), true), nil
+}
+
+func (e *Engine) allowed(projectID uuid.UUID) func(string) bool {
+	return func(name string) bool {
+		return e.categories.Allowed(projectID, name)
+	}
+}
+
+func run(format string, rules []*compiledRule, l Log, allowed func(string) bool, dryRun bool) Outcome {
+	out := Outcome{Log: l}
+	parse := format != "" && format != FormatNone
+	if !parse && len(rules) == 0 {
+		return out
+	}
+
+	// rules may add to these, the caller's maps and slices stay untouched
+	out.Log.Metadata = make(map[string]string, len(l.Metadata))
+	for k, v := range l.Metadata {
+		out.Log.Metadata[k] = v
+	}
+	out.Log.Tags = append([]string(nil), l.Tags...)
+	out.Log.Category = category.Normalize(l.Category)
+
+	if parse {
+		note, parsed := parseBody(format, &out.Log, allowed, time.Now())
+		if dryRun {
+			out.Steps = append(out.Steps, Step{Matched: parsed, Note: note})
+		}
+	}
+
+	for _, c := range rules {
+		step := Step{Rule: c.rule, Matched: c.matches(out.Log)}
+		if step.Matched {
+			step.Note = c.apply(&out, dryRun)
+		}
+		if dryRun {
+			out.Steps = append(out.Steps, step)
+		}
+		if out.Dropped {
+			break
+		}
+	}
+	return out
+}
+
+func (c *compiledRule) matches(l Log) bool {
+	if c.rule.MatchCategory != "" && c.rule.MatchCategory != l.Category {
+		return false
+	}
+	return c.match == nil || c.match.MatchString(l.Message)
+}
+
+// apply runs the action of a matching rule and returns a note for dry runs.
+func (c *compiledRule) apply(out *Outcome, dryRun bool) string {
+	rule := c.rule
+	switch rule.Action {
+	case processing.ActionDrop:
+		out.Dropped, out.DroppedBy = true, rule.Name
+		return "dropped"
+
+	case processing.ActionSample:
+		if dryRun {
+			return fmt.Sprintf("kept with a chance of %g%%", rule.SamplePercent)
+		}
+		if rand.Float64()*100 >= rule.SamplePercent {
+			out.Dropped, out.DroppedBy = true, rule.Name
+			return "sampled out"
+		}
+		return "sampled in"
+
+	case processing.ActionRewrite:
+		if !c.pattern.MatchString(out.Log.Message) {
+			return "pattern didn't match"
+		}
+		out.Log.Message = c.pattern.ReplaceAllString(out.Log.Message, rule.Replacement)
+		return "message rewritten"
+
+	case processing.ActionExtract:
+		m := c.pattern.FindStringSubmatch(out.Log.Message)
+		if m == nil {
+			return "pattern didn't match"
+		}
+		extracted := 0
+		for i, name := range c.pattern.SubexpNames() {
+			if name != "" && i < len(m) {
+				out.Log.Metadata[name] = m[i]
+				extracted++
+			}
+		}
+		return fmt.Sprintf("extracted %d fields", extracted)
+
+	case processing.ActionRename:
+		out.Log.Category = rule.Target
+		return "category renamed to " + rule.Target
+
+	case processing.ActionTag:
+		for _, tag := range rule.Tags {
+			if !contains(out.Log.Tags, tag) {
+				out.Log.Tags = append(out.Log.Tags, tag)
+			}
+		}
+		return "tagged"
+	}
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
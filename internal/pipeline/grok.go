@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// grokPatterns are the named patterns usable as %{NAME} or %{NAME:field}.
+var grokPatterns = map[string]string{
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"INT":               `[+-]?\d+`,
+	"NUMBER":            `[+-]?(?:\d+(?:\.\d*)?|\.\d+)`,
+	"UUID":              `[0-9A-Fa-f]{8}-(?:[0-9A-Fa-f]{4}-){3}[0-9A-Fa-f]{12}`,
+	"IPV4":              `(?:\d{1,3}\.){3}\d{1,3}`,
+	"IP":                `(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f]*:[0-9A-Fa-f:.]+`,
+	"PATH":              `(?:/[^/\s]*)+`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"`,
+	"LOGLEVEL":          `(?i:trace|debug|info|notice|warn(?:ing)?|err(?:or)?|crit(?:ical)?|fatal|panic)`,
+	"HTTPMETHOD":        `GET|HEAD|POST|PUT|PATCH|DELETE|OPTIONS|CONNECT|TRACE`,
+	"TIMESTAMP_ISO8601": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?`,
+}
+
+var grokToken = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+
+// GrokPatterns returns the names usable in grok expressions.
+func GrokPatterns() []string {
+	names := make([]string, 0, len(grokPatterns))
+	for name := range grokPatterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compileGrok turns a grok expression into a regular expression, every
+// %{NAME:field} becomes a named group. Text around the tokens is taken as a
+// regular expression, so plain regular expressions with named groups work
+// as well.
+func compileGrok(expr string) (*regexp.Regexp, error) {
+	var unknown string
+	translated := grokToken.ReplaceAllStringFunc(expr, func(token string) string {
+		m := grokToken.FindStringSubmatch(token)
+		pattern, ok := grokPatterns[m[1]]
+		if !ok {
+			unknown = m[1]
+			return token
+		}
+		if m[2] == "" {
+			return "(?:" + pattern + ")"
+		}
+		return "(?P<" + m[2] + ">" + pattern + ")"
+	})
+	if unknown != "" {
+		return nil, fmt.Errorf("unknown grok pattern %q", unknown)
+	}
+	return regexp.Compile(translated)
+}
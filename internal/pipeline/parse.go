@@ -0,0 +1,243 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AjayShukla007/logsentinel/internal/category"
+)
+
+// Body formats a project parses messages with. FormatNone stores messages
+// as sent, FormatAuto detects JSON and logfmt.
+const (
+	FormatNone   = "none"
+	FormatAuto   = "auto"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+// ValidFormat reports whether format is a known body format.
+func ValidFormat(format string) bool {
+	return format == FormatNone || format == FormatAuto || format == FormatJSON || format == FormatLogfmt
+}
+
+const (
+	// maxTimestampAge and maxTimestampAhead bound lifted timestamps, values
+	// outside are more likely a wrong clock or another field than the time
+	// of the log and stay in the metadata
+	maxTimestampAge   = 24 * time.Hour
+	maxTimestampAhead = 5 * time.Minute
+	// maxTraceIDLength is the size of the trace_id column, longer values stay
+	// in the metadata
+	maxTraceIDLength = 128
+)
+
+// Keys lifted out of parsed bodies, the first key present wins.
+var (
+	levelKeys   = []string{"level", "lvl", "severity"}
+	timeKeys    = []string{"ts", "time", "timestamp", "@timestamp"}
+	messageKeys = []string{"msg", "message"}
+	traceKeys   = []string{"trace_id", "traceId", "trace.id"}
+)
+
+// parseBody parses the message of l in the given format. Well-known keys
+// become the category, time, message and trace ID of the log, every other
+// key is added to its metadata without overriding metadata sent with the
+// log. Levels that aren't an allowed category stay in the metadata. A
+// message that doesn't parse is left as is.
+func parseBody(format string, l *Log, allowed func(string) bool, now time.Time) (string, bool) {
+	var fields map[string]string
+	var err error
+	body := strings.TrimSpace(l.Message)
+
+	switch format {
+	case FormatJSON:
+		fields, err = parseJSON(body)
+	case FormatLogfmt:
+		fields, err = parseLogfmt(body)
+	case FormatAuto:
+		if strings.HasPrefix(body, "{") {
+			format = FormatJSON
+			fields, err = parseJSON(body)
+		} else {
+			format = FormatLogfmt
+			fields, err = parseLogfmt(body)
+		}
+	default:
+		return "", false
+	}
+	if err != nil {
+		return fmt.Sprintf("not %s: %v", format, err), false
+	}
+
+	if key, level := lift(fields, levelKeys); key != "" {
+		if c := category.Normalize(level); allowed(c) {
+			l.Category = c
+			delete(fields, key)
+		}
+	}
+	if key, ts := lift(fields, timeKeys); key != "" {
+		if t, ok := parseTimestamp(ts); ok && !t.Before(now.Add(-maxTimestampAge)) && !t.After(now.Add(maxTimestampAhead)) {
+			l.Time = t
+			delete(fields, key)
+		}
+	}
+	if key, traceID := lift(fields, traceKeys); key != "" && len(traceID) <= maxTraceIDLength {
+		l.TraceID = traceID
+		delete(fields, key)
+	}
+	if key, msg := lift(fields, messageKeys); key != "" {
+		l.Message = msg
+		delete(fields, key)
+	}
+
+	if l.Metadata == nil {
+		l.Metadata = make(map[string]string, len(fields))
+	}
+	for k, v := range fields {
+		if _, ok := l.Metadata[k]; !ok {
+			l.Metadata[k] = v
+		}
+	}
+	return fmt.Sprintf("parsed %d %s fields", len(fields), format), true
+}
+
+func lift(fields map[string]string, keys []string) (string, string) {
+	for _, key := range keys {
+		if v, ok := fields[key]; ok {
+			return key, v
+		}
+	}
+	return "", ""
+}
+
+// parseTimestamp accepts RFC 3339 and unix seconds, milliseconds or
+// nanoseconds.
+func parseTimestamp(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case f >= 1e17:
+		return time.Unix(0, int64(f)).UTC(), true
+	case f >= 1e11:
+		return time.UnixMilli(int64(f)).UTC(), true
+	default:
+		sec, frac := int64(f), f-float64(int64(f))
+		return time.Unix(sec, int64(frac*1e9)).UTC(), true
+	}
+}
+
+// parseJSON parses a JSON object. Nested objects are flattened into dotted
+// keys, arrays are kept as JSON.
+func parseJSON(body string) (map[string]string, error) {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data after object")
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("not an object")
+	}
+
+	fields := make(map[string]string, len(obj))
+	flatten(fields, "", obj)
+	return fields, nil
+}
+
+func flatten(fields map[string]string, prefix string, obj map[string]any) {
+	for k, v := range obj {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(fields, key+".", v)
+		case string:
+			fields[key] = v
+		case nil:
+			fields[key] = ""
+		case json.Number:
+			fields[key] = v.String()
+		case bool:
+			fields[key] = strconv.FormatBool(v)
+		default:
+			var b bytes.Buffer
+			enc := json.NewEncoder(&b)
+			enc.SetEscapeHTML(false)
+			enc.Encode(v)
+			fields[key] = strings.TrimSuffix(b.String(), "\n")
+		}
+	}
+}
+
+// parseLogfmt parses key=value pairs separated by spaces, values may be
+// double quoted. Every token must be a pair so plain text isn't mistaken
+// for logfmt.
+func parseLogfmt(body string) (map[string]string, error) {
+	fields := make(map[string]string)
+	i := 0
+	for {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		if i == len(body) {
+			break
+		}
+
+		start := i
+		for i < len(body) && body[i] != '=' && body[i] != ' ' && body[i] != '"' {
+			i++
+		}
+		key := body[start:i]
+		if key == "" || i == len(body) || body[i] != '=' {
+			return nil, fmt.Errorf("expected key=value at offset %d", start)
+		}
+		i++
+
+		var value string
+		if i < len(body) && body[i] == '"' {
+			end := i + 1
+			for end < len(body) && body[end] != '"' {
+				if body[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(body) {
+				return nil, fmt.Errorf("unterminated quote at offset %d", i)
+			}
+			unquoted, err := strconv.Unquote(body[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value at offset %d", i)
+			}
+			value, i = unquoted, end+1
+			if i < len(body) && body[i] != ' ' {
+				return nil, fmt.Errorf("expected space at offset %d", i)
+			}
+		} else {
+			end := i
+			for end < len(body) && body[end] != ' ' {
+				end++
+			}
+			value, i = body[i:end], end
+		}
+
+		fields[key] = value
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields")
+	}
+	return fields, nil
+}
@@ -3,28 +3,52 @@ package ratelimit
 import (
 	"sync"
 	"time"
+
+	"github.com/AjayShukla007/logsentinel/internal/metrics"
 )
 
 type RateLimiter struct {
-	limits map[string]*userLimit
-	mu     sync.RWMutex
+	limits   map[string]*userLimit
+	mu       sync.RWMutex
+	onReject func(clientID string, limit int)
+
+	freeLogsPerMinute int
 }
 
 type userLimit struct {
 	count    int
 	window   time.Time
 	isProAcc bool
+	rejected bool
 }
 
-func NewRateLimiter() *RateLimiter {
+// DefaultFreeLogsPerMinute is the number of logs a free account may send per
+// minute unless configured otherwise
+const DefaultFreeLogsPerMinute = 100
+
+func NewRateLimiter(freeLogsPerMinute int) *RateLimiter {
 	return &RateLimiter{
-		limits: make(map[string]*userLimit),
+		limits:            make(map[string]*userLimit),
+		freeLogsPerMinute: freeLogsPerMinute,
 	}
 }
 
-func (r *RateLimiter) AllowLog(clientID string, isProAccount bool) bool {
+// FreeLogsPerMinute returns the number of logs a free account may send per
+// minute.
+func (r *RateLimiter) FreeLogsPerMinute() int {
+	return r.freeLogsPerMinute
+}
+
+// OnReject registers a callback for the first rejected log of a client in
+// each window, later rejections in the same window don't call it again.
+func (r *RateLimiter) OnReject(fn func(clientID string, limit int)) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.onReject = fn
+}
+
+func (r *RateLimiter) AllowLog(clientID string, isProAccount bool) bool {
+	r.mu.Lock()
 
 	now := time.Now()
 	limit, exists := r.limits[clientID]
@@ -35,13 +59,24 @@ func (r *RateLimiter) AllowLog(clientID string, isProAccount bool) bool {
 			window:   now,
 			isProAcc: isProAccount,
 		}
+		r.mu.Unlock()
 		return true
 	}
 
-	if !limit.isProAcc && limit.count >= 100 {
+	if !limit.isProAcc && limit.count >= r.freeLogsPerMinute {
+		metrics.RateLimited.Inc()
+		notify := !limit.rejected && r.onReject != nil
+		limit.rejected = true
+		onReject := r.onReject
+		r.mu.Unlock()
+
+		if notify {
+			onReject(clientID, r.freeLogsPerMinute)
+		}
 		return false
 	}
 
 	limit.count++
+	r.mu.Unlock()
 	return true
 }
@@ -0,0 +1,170 @@
+package redact
+
+import (
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Built-in detector names.
+const (
+	DetectorEmail        = "email"
+	DetectorCreditCard   = "credit_card"
+	DetectorJWT          = "jwt"
+	DetectorBearerToken  = "bearer_token"
+	DetectorAWSAccessKey = "aws_access_key"
+	DetectorAWSSecretKey = "aws_secret_key"
+	DetectorIPv4         = "ipv4"
+	DetectorIPv6         = "ipv6"
+)
+
+// detector finds sensitive values. With group set only that submatch is
+// redacted so the surrounding key stays readable, valid rejects candidates
+// the pattern alone can't tell apart.
+type detector struct {
+	name    string
+	pattern *regexp.Regexp
+	group   int
+	valid   func(string) bool
+}
+
+// builtins run in this order, tokens go first so their parts aren't taken
+// for other values.
+var builtins = []detector{
+	{
+		name:    DetectorJWT,
+		pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	},
+	{
+		name:    DetectorBearerToken,
+		pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]+=*)`),
+		group:   1,
+	},
+	{
+		name:    DetectorAWSAccessKey,
+		pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b`),
+	},
+	{
+		name:    DetectorAWSSecretKey,
+		pattern: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+		group:   1,
+	},
+	{
+		name:    DetectorEmail,
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	{
+		name:    DetectorCreditCard,
+		pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid:   cardNumber,
+	},
+	{
+		name:    DetectorIPv4,
+		pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		valid:   func(s string) bool { return net.ParseIP(s) != nil },
+	},
+	{
+		name:    DetectorIPv6,
+		pattern: regexp.MustCompile(`(?i)[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}(?:%[0-9a-z]+)?`),
+		valid: func(s string) bool {
+			ip := net.ParseIP(strings.SplitN(s, "%", 2)[0])
+			return ip != nil && ip.To4() == nil
+		},
+	},
+}
+
+// Builtins returns the names of the built-in detectors.
+func Builtins() []string {
+	names := make([]string, len(builtins))
+	for i, d := range builtins {
+		names[i] = d.name
+	}
+	return names
+}
+
+// IsBuiltin reports whether name is a built-in detector.
+func IsBuiltin(name string) bool {
+	for _, d := range builtins {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cardIssuer is a range of issuer identification numbers (IINs), the
+// leading digits of a card number, and the lengths the issuer uses.
+type cardIssuer struct {
+	low, high int
+	lengths   []int
+}
+
+// cardIssuers lists the major card networks. Numbers matching none of them
+// are order ids, timestamps and the like that happen to pass the checksum.
+var cardIssuers = []cardIssuer{
+	{low: 4, high: 4, lengths: []int{13, 16, 19}},                 // Visa
+	{low: 51, high: 55, lengths: []int{16}},                       // Mastercard
+	{low: 2221, high: 2720, lengths: []int{16}},                   // Mastercard
+	{low: 34, high: 34, lengths: []int{15}},                       // American Express
+	{low: 37, high: 37, lengths: []int{15}},                       // American Express
+	{low: 6011, high: 6011, lengths: []int{16, 17, 18, 19}},       // Discover
+	{low: 644, high: 649, lengths: []int{16, 17, 18, 19}},         // Discover
+	{low: 65, high: 65, lengths: []int{16, 17, 18, 19}},           // Discover
+	{low: 300, high: 305, lengths: []int{14, 15, 16, 17, 18, 19}}, // Diners Club
+	{low: 36, high: 36, lengths: []int{14, 15, 16, 17, 18, 19}},   // Diners Club
+	{low: 38, high: 39, lengths: []int{16, 17, 18, 19}},           // Diners Club
+	{low: 3528, high: 3589, lengths: []int{16, 17, 18, 19}},       // JCB
+	{low: 62, high: 62, lengths: []int{16, 17, 18, 19}},           // UnionPay
+}
+
+// cardNumber checks that a candidate has the prefix and length of a known
+// issuer and a valid Luhn checksum, separators are ignored.
+func cardNumber(s string) bool {
+	var digits []int
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	return knownIssuer(digits) && luhn(digits)
+}
+
+// knownIssuer reports whether the leading digits and the length of a card
+// number match an issuer.
+func knownIssuer(digits []int) bool {
+	for _, issuer := range cardIssuers {
+		if !slices.Contains(issuer.lengths, len(digits)) {
+			continue
+		}
+		prefix := 0
+		for _, d := range digits[:len(strconv.Itoa(issuer.low))] {
+			prefix = prefix*10 + d
+		}
+		if prefix >= issuer.low && prefix <= issuer.high {
+			return true
+		}
+	}
+	return false
+}
+
+// luhn checks the checksum digit of a card number.
+func luhn(digits []int) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
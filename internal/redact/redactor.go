@@ -0,0 +1,394 @@
+package redact
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/reload"
+	"github.com/AjayShukla007/logsentinel/internal/repository/redaction"
+)
+
+// Actions taken on a detected value. Drop discards the whole log.
+const (
+	ActionMask = "mask"
+	ActionHash = "hash"
+	ActionDrop = "drop"
+)
+
+const (
+	// defaultFlushInterval is how often redaction counts are written
+	defaultFlushInterval = 10 * time.Second
+	// finalFlushTimeout bounds the last flush on Stop when the shutdown
+	// deadline has already passed
+	finalFlushTimeout = 5 * time.Second
+)
+
+type Config struct {
+	// DefaultAction is applied by built-in detectors a project didn't
+	// override
+	DefaultAction string
+	// Disabled lists the built-in detectors that are off unless a project
+	// enables them
+	Disabled []string
+	// HashKey keys the hashes of the hash action, so hashed values can be
+	// correlated but not brute forced. Without one a random key is used and
+	// hashes change with every restart.
+	HashKey []byte
+}
+
+func DefaultConfig() Config {
+	return Config{DefaultAction: ActionMask}
+}
+
+// ValidAction reports whether action is a known action.
+func ValidAction(action string) bool {
+	return action == ActionMask || action == ActionHash || action == ActionDrop
+}
+
+type rule struct {
+	detector
+	action string
+}
+
+// Result is a log after redaction. A dropped log has no message or
+// metadata, DroppedBy names the detector that dropped it. Counts holds how
+// many values each detector found.
+type Result struct {
+	Message   string
+	Metadata  map[string]string
+	Dropped   bool
+	DroppedBy string
+	Counts    map[string]int
+}
+
+type countKey struct {
+	projectID uuid.UUID
+	detector  string
+	action    string
+	day       time.Time
+}
+
+// Redactor removes sensitive values from logs before they are stored. Every
+// project runs the built-in detectors with the configured defaults, its
+// rules override the action of a built-in or add custom patterns. Rules are
+// cached and reloaded periodically, counts of redacted values are written
+// like the usage rollups.
+type Redactor struct {
+	repo     redaction.Repository
+	config   Config
+	hashKey  []byte
+	defaults []rule
+
+	mu       sync.RWMutex
+	policies map[uuid.UUID][]rule
+
+	countMu sync.Mutex
+	pending map[countKey]int64
+
+	reloads  *reload.Loop
+	quit     chan struct{}
+	stopOnce sync.Once
+	flushing sync.WaitGroup
+}
+
+func NewRedactor(repo redaction.Repository, config Config) (*Redactor, error) {
+	if !ValidAction(config.DefaultAction) {
+		return nil, fmt.Errorf("invalid default redaction action %q", config.DefaultAction)
+	}
+	for _, name := range config.Disabled {
+		if !IsBuiltin(name) {
+			return nil, fmt.Errorf("unknown redaction detector %q", name)
+		}
+	}
+
+	hashKey := config.HashKey
+	if len(hashKey) == 0 {
+		hashKey = make([]byte, 32)
+		if _, err := rand.Read(hashKey); err != nil {
+			return nil, fmt.Errorf("generating redaction hash key: %w", err)
+		}
+		slog.Warn("No redaction hash key configured, hashed values change on restart")
+	}
+
+	r := &Redactor{
+		repo:     repo,
+		config:   config,
+		hashKey:  hashKey,
+		policies: make(map[uuid.UUID][]rule),
+		pending:  make(map[countKey]int64),
+		quit:     make(chan struct{}),
+	}
+	r.defaults = r.policy(nil)
+	r.reloads = reload.New("redaction rules", r.Reload)
+	return r, nil
+}
+
+// Start loads the rules, then reloads them and flushes the counts
+// periodically until Stop is called.
+func (r *Redactor) Start(ctx context.Context) error {
+	if err := r.reloads.Start(ctx); err != nil {
+		return err
+	}
+
+	r.flushing.Add(1)
+	go func() {
+		defer r.flushing.Done()
+
+		ticker := time.NewTicker(defaultFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.flush(context.Background())
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the periodic work and writes the remaining counts. The counts
+// are written even when the background work didn't stop in time, they would
+// be lost otherwise.
+func (r *Redactor) Stop(ctx context.Context) error {
+	err := r.reloads.Stop(ctx)
+
+	r.stopOnce.Do(func() { close(r.quit) })
+	stopped := make(chan struct{})
+	go func() {
+		r.flushing.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), finalFlushTimeout)
+		defer cancel()
+	}
+	return errors.Join(err, r.flush(ctx))
+}
+
+// Reload replaces the cached policies with the rules in the database.
+func (r *Redactor) Reload(ctx context.Context) error {
+	rules, err := r.repo.ListAllRules(ctx)
+	if err != nil {
+		return fmt.Errorf("loading redaction rules: %w", err)
+	}
+
+	byProject := make(map[uuid.UUID][]*redaction.Rule)
+	for _, pr := range rules {
+		byProject[pr.ProjectID] = append(byProject[pr.ProjectID], pr)
+	}
+
+	policies := make(map[uuid.UUID][]rule, len(byProject))
+	for projectID, projectRules := range byProject {
+		policies[projectID] = r.policy(projectRules)
+	}
+
+	r.mu.Lock()
+	r.policies = policies
+	r.mu.Unlock()
+	return nil
+}
+
+// policy builds the detectors of a project from the defaults and its rules.
+func (r *Redactor) policy(projectRules []*redaction.Rule) []rule {
+	overrides := make(map[string]*redaction.Rule)
+	var custom []rule
+	for _, pr := range projectRules {
+		if pr.Detector != redaction.DetectorCustom {
+			overrides[pr.Detector] = pr
+			continue
+		}
+		if !pr.Enabled {
+			continue
+		}
+		pattern, err := regexp.Compile(pr.Pattern)
+		if err != nil {
+			slog.Warn("Skipping redaction rule with invalid pattern", "rule", pr.ID, "err", err)
+			continue
+		}
+		custom = append(custom, rule{detector: detector{name: pr.Name, pattern: pattern}, action: pr.Action})
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range r.config.Disabled {
+		disabled[name] = true
+	}
+
+	var policy []rule
+	for _, d := range builtins {
+		action, enabled := r.config.DefaultAction, !disabled[d.name]
+		if o, ok := overrides[d.name]; ok {
+			action, enabled = o.Action, o.Enabled
+		}
+		if enabled {
+			policy = append(policy, rule{detector: d, action: action})
+		}
+	}
+	return append(policy, custom...)
+}
+
+// Apply redacts the message and metadata values of a log of the project and
+// counts the redacted values.
+func (r *Redactor) Apply(projectID uuid.UUID, message string, metadata map[string]string) Result {
+	policy, counts, result := r.apply(projectID, message, metadata)
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	r.countMu.Lock()
+	for i, rl := range policy {
+		if counts[i] == 0 {
+			continue
+		}
+		r.pending[countKey{projectID: projectID, detector: rl.name, action: rl.action, day: day}] += int64(counts[i])
+	}
+	r.countMu.Unlock()
+
+	return result
+}
+
+// Preview redacts a log like Apply without counting, for dry runs.
+func (r *Redactor) Preview(projectID uuid.UUID, message string, metadata map[string]string) Result {
+	_, _, result := r.apply(projectID, message, metadata)
+	return result
+}
+
+func (r *Redactor) apply(projectID uuid.UUID, message string, metadata map[string]string) ([]rule, []int, Result) {
+	r.mu.RLock()
+	policy, ok := r.policies[projectID]
+	r.mu.RUnlock()
+	if !ok {
+		policy = r.defaults
+	}
+
+	counts := make([]int, len(policy))
+	result := Result{Message: message}
+	for i, rl := range policy {
+		var n int
+		result.Message, n = r.redact(rl, result.Message)
+		counts[i] += n
+	}
+	if len(metadata) > 0 {
+		result.Metadata = make(map[string]string, len(metadata))
+		for key, value := range metadata {
+			for i, rl := range policy {
+				var n int
+				value, n = r.redact(rl, value)
+				counts[i] += n
+			}
+			result.Metadata[key] = value
+		}
+	}
+
+	found := make(map[string]int)
+	for i, rl := range policy {
+		if counts[i] == 0 {
+			continue
+		}
+		found[rl.name] += counts[i]
+		if rl.action == ActionDrop && !result.Dropped {
+			result = Result{Dropped: true, DroppedBy: rl.name}
+		}
+	}
+	result.Counts = found
+
+	return policy, counts, result
+}
+
+// redact replaces the values the rule detects in s and returns how many it
+// replaced. Values of drop rules are counted but left in place, the caller
+// drops the whole log.
+func (r *Redactor) redact(rl rule, s string) (string, int) {
+	matches := rl.pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, 0
+	}
+
+	var b strings.Builder
+	last, n := 0, 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if rl.group > 0 {
+			start, end = m[2*rl.group], m[2*rl.group+1]
+			if start < 0 {
+				continue
+			}
+		}
+		value := s[start:end]
+		if rl.valid != nil && !rl.valid(value) {
+			continue
+		}
+		n++
+
+		b.WriteString(s[last:start])
+		b.WriteString(r.replacement(rl, value))
+		last = end
+	}
+	if n == 0 || rl.action == ActionDrop {
+		return s, n
+	}
+	b.WriteString(s[last:])
+	return b.String(), n
+}
+
+func (r *Redactor) replacement(rl rule, value string) string {
+	if rl.action == ActionHash {
+		mac := hmac.New(sha256.New, r.hashKey)
+		mac.Write([]byte(value))
+		return "[" + rl.name + ":" + hex.EncodeToString(mac.Sum(nil))[:16] + "]"
+	}
+	return "[REDACTED:" + rl.name + "]"
+}
+
+func (r *Redactor) flush(ctx context.Context) error {
+	r.countMu.Lock()
+	pending := r.pending
+	r.pending = make(map[countKey]int64)
+	r.countMu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	counts := make([]redaction.Count, 0, len(pending))
+	for k, n := range pending {
+		counts = append(counts, redaction.Count{
+			ProjectID: k.projectID,
+			Detector:  k.detector,
+			Action:    k.action,
+			Day:       k.day,
+			Count:     n,
+		})
+	}
+
+	if err := r.repo.AddCounts(ctx, counts); err != nil {
+		slog.Error("Error flushing redaction counts, retrying next flush", "err", err)
+
+		r.countMu.Lock()
+		for k, n := range pending {
+			r.pending[k] += n
+		}
+		r.countMu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package rehydrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/repository/rehydration"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+const expiryJobName = "rehydration_expiry"
+
+var (
+	ErrArchiveDisabled = errors.New("cold archive is not configured")
+	ErrTooManyRows     = errors.New("time range holds more archived logs than a rehydration may restore")
+)
+
+type Config struct {
+	// DefaultTTL is how long restored logs stay queryable when the request
+	// doesn't say
+	DefaultTTL time.Duration
+	// MaxTTL caps the requested lifetime of a rehydration
+	MaxTTL time.Duration
+	// MaxRows caps the number of logs restored by a single rehydration
+	MaxRows int64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		DefaultTTL: 24 * time.Hour,
+		MaxTTL:     7 * 24 * time.Hour,
+		MaxRows:    1_000_000,
+	}
+}
+
+// Rehydrator restores archived logs into a dedicated partition of
+// restored_logs, which is dropped again once the rehydration expires.
+// Restores run in the background, Stop cancels them and waits for them to
+// record their result.
+type Rehydrator struct {
+	db       *pgxpool.Pool
+	archiver *archive.Archiver
+	repo     rehydration.Repository
+	config   Config
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRehydrator creates the rehydrator, archiver may be nil in which case
+// only the expiry of earlier rehydrations keeps working.
+func NewRehydrator(db *pgxpool.Pool, archiver *archive.Archiver, repo rehydration.Repository, config Config) *Rehydrator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Rehydrator{
+		db:       db,
+		archiver: archiver,
+		repo:     repo,
+		config:   config,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Stop cancels running restores and waits until they recorded their result.
+func (r *Rehydrator) Stop(ctx context.Context) error {
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterJobs adds the job dropping expired rehydrations.
+func (r *Rehydrator) RegisterJobs(sched *scheduler.Scheduler) error {
+	return sched.Register(scheduler.Job{
+		Name:     expiryJobName,
+		Schedule: "*/15 * * * *",
+		Timeout:  5 * time.Minute,
+		Jitter:   time.Minute,
+		Run:      r.expire,
+	})
+}
+
+// Rehydrate starts restoring the archived logs of a project in [from, to)
+// and returns the rehydration while it is still restoring. Once ready the
+// logs are queryable through restored_logs and searchable_logs until the
+// rehydration expires. Ranges overlapping an active rehydration of the
+// project are rejected with rehydration.ErrOverlap.
+func (r *Rehydrator) Rehydrate(ctx context.Context, projectID uuid.UUID, from, to time.Time, ttl time.Duration) (*rehydration.Rehydration, error) {
+	if r.archiver == nil {
+		return nil, ErrArchiveDisabled
+	}
+
+	if ttl <= 0 {
+		ttl = r.config.DefaultTTL
+	}
+	if ttl > r.config.MaxTTL {
+		ttl = r.config.MaxTTL
+	}
+
+	files, err := r.archiver.Files(ctx, projectID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive manifests: %w", err)
+	}
+
+	var estimate int64
+	for _, file := range files {
+		estimate += int64(file.Rows)
+	}
+	if estimate > r.config.MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	result := &rehydration.Rehydration{
+		ID:         uuid.New(),
+		ProjectID:  projectID,
+		RangeStart: from,
+		RangeEnd:   to,
+		Status:     rehydration.StatusRestoring,
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+	if err := r.repo.Create(ctx, result); err != nil {
+		return nil, err
+	}
+
+	restoring := *result
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(&restoring, files)
+	}()
+
+	return result, nil
+}
+
+// run restores a rehydration and records whether it became ready or failed.
+func (r *Rehydrator) run(result *rehydration.Rehydration, files []archive.ManifestFile) {
+	rows, err := r.restore(r.ctx, result, files)
+
+	// record the result even if the restore was cancelled by Stop
+	finishCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err != nil {
+		slog.Error("Error rehydrating logs", "project_id", result.ProjectID, "rehydration", result.ID, "err", err)
+		r.dropPartition(finishCtx, result.ID)
+		if updateErr := r.repo.UpdateStatus(finishCtx, result.ID, rehydration.StatusFailed, 0, err.Error()); updateErr != nil {
+			slog.Error("Error recording failed rehydration", "rehydration", result.ID, "err", updateErr)
+		}
+		return
+	}
+
+	if err := r.repo.UpdateStatus(finishCtx, result.ID, rehydration.StatusReady, rows, ""); err != nil {
+		slog.Error("Error recording rehydration", "rehydration", result.ID, "err", err)
+		return
+	}
+
+	slog.Info("Rehydrated logs", "project_id", result.ProjectID, "rows", rows, "partition", PartitionName(result.ID))
+}
+
+func (r *Rehydrator) List(ctx context.Context, projectID uuid.UUID) ([]*rehydration.Rehydration, error) {
+	return r.repo.ListByProject(ctx, projectID)
+}
+
+func (r *Rehydrator) restore(ctx context.Context, result *rehydration.Rehydration, files []archive.ManifestFile) (int64, error) {
+	partition := PartitionName(result.ID)
+	_, err := r.db.Exec(ctx, fmt.Sprintf(
+		`CREATE TABLE %s PARTITION OF restored_logs FOR VALUES IN ('%s')`,
+		pgx.Identifier{partition}.Sanitize(), result.ID,
+	))
+	if err != nil {
+		return 0, fmt.Errorf("creating restore partition: %w", err)
+	}
+
+	var total int64
+	for _, file := range files {
+		records, err := r.archiver.Read(ctx, file)
+		if err != nil {
+			return total, err
+		}
+
+		rows := make([][]any, 0, len(records))
+		for _, record := range records {
+			if record.CreatedAt.Before(result.RangeStart) || !record.CreatedAt.Before(result.RangeEnd) {
+				continue
+			}
+			id, err := uuid.Parse(record.ID)
+			if err != nil {
+				return total, fmt.Errorf("invalid log id in %s: %w", file.Key, err)
+			}
+			metadata, tags := record.Metadata, record.Tags
+			if metadata == nil {
+				metadata = map[string]string{}
+			}
+			if tags == nil {
+				tags = []string{}
+			}
+			rows = append(rows, []any{
+				result.ID, id, result.ProjectID, record.Category, record.Message, record.CreatedAt,
+				metadata, tags, nullable(record.TraceID), nullable(record.PatternID), record.LoggedAt,
+			})
+		}
+
+		copied, err := r.db.CopyFrom(ctx,
+			pgx.Identifier{partition},
+			[]string{"rehydration_id", "id", "project_id", "category", "message", "created_at", "metadata", "tags", "trace_id", "pattern_id", "logged_at"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return total, fmt.Errorf("restoring %s: %w", file.Key, err)
+		}
+		total += copied
+	}
+
+	return total, nil
+}
+
+// expire drops the partitions of rehydrations past their expiry.
+func (r *Rehydrator) expire(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+
+	expired, err := r.repo.ListExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, rehydrated := range expired {
+		if err := r.dropPartition(ctx, rehydrated.ID); err != nil {
+			return err
+		}
+		if err := r.repo.UpdateStatus(ctx, rehydrated.ID, rehydration.StatusExpired, rehydrated.RowsRestored, rehydrated.Error); err != nil {
+			return err
+		}
+		run.RowsAffected += rehydrated.RowsRestored
+	}
+
+	return nil
+}
+
+func (r *Rehydrator) dropPartition(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, pgx.Identifier{PartitionName(id)}.Sanitize()))
+	if err != nil {
+		slog.Error("Error dropping restore partition", "rehydration", id, "err", err)
+	}
+	return err
+}
+
+// nullable stores empty optional fields as NULL like ingestion does.
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// PartitionName is the restored_logs partition holding a rehydration.
+func PartitionName(id uuid.UUID) string {
+	return "restored_logs_" + strings.ReplaceAll(id.String(), "-", "")
+}
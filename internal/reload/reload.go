@@ -0,0 +1,97 @@
+// Package reload keeps caches of database backed settings current.
+package reload
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Interval is how often every replica reloads its caches. Changes made
+// through a replica apply to its own cache right away, the other replicas
+// pick them up within one interval.
+const Interval = 30 * time.Second
+
+// Timeout bounds one periodic reload, a reload stuck on the database must
+// not hold up the next one or Stop.
+const Timeout = 10 * time.Second
+
+// Loop loads a cache on Start and reloads it every Interval until Stop is
+// called. A failed reload keeps the cache as it was.
+type Loop struct {
+	what   string
+	reload func(ctx context.Context) error
+
+	quit     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup
+}
+
+// New creates a loop calling reload, what names the cache in error logs.
+func New(what string, reload func(ctx context.Context) error) *Loop {
+	return &Loop{
+		what:   what,
+		reload: reload,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start loads the cache and starts reloading it periodically. Nothing runs
+// in the background when the first load fails.
+func (l *Loop) Start(ctx context.Context) error {
+	if err := l.reload(ctx); err != nil {
+		return err
+	}
+
+	l.running.Add(1)
+	go func() {
+		defer l.running.Done()
+
+		ticker := time.NewTicker(Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				l.reloadOnce()
+			case <-l.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (l *Loop) reloadOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	if err := l.reload(ctx); err != nil {
+		slog.Error("Error reloading "+l.what, "err", err)
+	}
+}
+
+// Stop stops the periodic reloads and waits for a running one to finish. It
+// returns right away when the loop never started and may be called more
+// than once.
+func (l *Loop) Stop(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.quit) })
+	return wait(ctx, &l.running)
+}
+
+// wait waits for wg until ctx is done.
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoopStop(t *testing.T) {
+	errLoad := errors.New("database unavailable")
+
+	tests := []struct {
+		name string
+		// start is called before Stop, nil leaves the loop unstarted
+		start func(l *Loop) error
+		// load is returned by the first load
+		load    error
+		wantErr error
+	}{
+		{name: "never started"},
+		{name: "started", start: func(l *Loop) error { return l.Start(context.Background()) }},
+		{name: "failed start", start: func(l *Loop) error { return l.Start(context.Background()) }, load: errLoad, wantErr: errLoad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New("test cache", func(ctx context.Context) error { return tt.load })
+			if tt.start != nil {
+				if err := tt.start(l); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Start = %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			for i := 0; i < 2; i++ {
+				if err := l.Stop(ctx); err != nil {
+					t.Fatalf("Stop #%d = %v, want it to return right away", i+1, err)
+				}
+			}
+		})
+	}
+}
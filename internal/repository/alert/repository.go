@@ -0,0 +1,278 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	KindThreshold = "threshold"
+	KindPattern   = "pattern"
+
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+type Rule struct {
+	ID        uuid.UUID     `json:"id"`
+	ProjectID uuid.UUID     `json:"project_id"`
+	Name      string        `json:"name"`
+	Kind      string        `json:"kind"`
+	Category  string        `json:"category"`
+	Pattern   string        `json:"pattern"`
+	Threshold int64         `json:"threshold"`
+	Window    time.Duration `json:"window"`
+	Enabled   bool          `json:"enabled"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Alert is one firing period of a rule, a rule has at most one open alert.
+type Alert struct {
+	ID         uuid.UUID  `json:"id"`
+	RuleID     uuid.UUID  `json:"rule_id"`
+	RuleName   string     `json:"rule_name"`
+	ProjectID  uuid.UUID  `json:"project_id"`
+	State      string     `json:"state"`
+	Value      int64      `json:"value"`
+	MatchCount int64      `json:"match_count"`
+	Message    string     `json:"message"`
+	FiredAt    time.Time  `json:"fired_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+type Repository interface {
+	CreateRule(ctx context.Context, rule *Rule) error
+	UpdateRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error
+	ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error)
+	ListEnabledRules(ctx context.Context) ([]*Rule, error)
+	Fire(ctx context.Context, rule *Rule, value int64, message string) (*Alert, bool, error)
+	Resolve(ctx context.Context, ruleID uuid.UUID) (*Alert, error)
+	GetOpenAlert(ctx context.Context, ruleID uuid.UUID) (*Alert, error)
+	ListAlerts(ctx context.Context, projectID uuid.UUID, state string, limit int) ([]*Alert, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateRule(ctx context.Context, rule *Rule) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO alert_rules (id, project_id, name, kind, category, pattern, threshold, window_seconds, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`,
+		rule.ID, rule.ProjectID, rule.Name, rule.Kind, rule.Category, rule.Pattern,
+		rule.Threshold, int64(rule.Window.Seconds()), rule.Enabled,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *PostgresRepository) UpdateRule(ctx context.Context, rule *Rule) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE alert_rules
+		SET name = $3, kind = $4, category = $5, pattern = $6, threshold = $7,
+			window_seconds = $8, enabled = $9, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND project_id = $2
+		RETURNING created_at, updated_at`,
+		rule.ID, rule.ProjectID, rule.Name, rule.Kind, rule.Category, rule.Pattern,
+		rule.Threshold, int64(rule.Window.Seconds()), rule.Enabled,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrRuleNotFound
+	}
+	return err
+}
+
+// DeleteRule removes a rule together with its alert history.
+func (r *PostgresRepository) DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM alert_rules WHERE id = $1 AND project_id = $2`, ruleID, projectID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, name, kind, category, pattern, threshold, window_seconds,
+			enabled, created_at, updated_at
+		FROM alert_rules
+		WHERE project_id = $1
+		ORDER BY created_at`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+func (r *PostgresRepository) ListEnabledRules(ctx context.Context) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, name, kind, category, pattern, threshold, window_seconds,
+			enabled, created_at, updated_at
+		FROM alert_rules
+		WHERE enabled
+		ORDER BY project_id, created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+// Fire opens an alert for the rule or refreshes the open one. The returned
+// bool is true only when a new alert was opened, which is what deduplicates
+// notifications.
+func (r *PostgresRepository) Fire(ctx context.Context, rule *Rule, value int64, message string) (*Alert, bool, error) {
+	a := &Alert{RuleName: rule.Name}
+	var opened bool
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO alerts (rule_id, project_id, state, value, message)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (rule_id) WHERE resolved_at IS NULL DO UPDATE
+		SET value = EXCLUDED.value,
+			match_count = alerts.match_count + 1,
+			message = EXCLUDED.message,
+			last_seen_at = CURRENT_TIMESTAMP
+		RETURNING id, rule_id, project_id, state, value, match_count, COALESCE(message, ''),
+			fired_at, last_seen_at, resolved_at, xmax = 0`,
+		rule.ID, rule.ProjectID, StateFiring, value, message,
+	).Scan(
+		&a.ID, &a.RuleID, &a.ProjectID, &a.State, &a.Value, &a.MatchCount, &a.Message,
+		&a.FiredAt, &a.LastSeenAt, &a.ResolvedAt, &opened,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return a, opened, nil
+}
+
+// Resolve closes the open alert of a rule, it returns nil when the rule
+// wasn't firing.
+func (r *PostgresRepository) Resolve(ctx context.Context, ruleID uuid.UUID) (*Alert, error) {
+	rows, err := r.db.Query(ctx, `
+		UPDATE alerts a
+		SET state = $2, resolved_at = CURRENT_TIMESTAMP
+		FROM alert_rules r
+		WHERE a.rule_id = $1 AND a.resolved_at IS NULL AND r.id = a.rule_id
+		RETURNING a.id, a.rule_id, r.name, a.project_id, a.state, a.value, a.match_count,
+			COALESCE(a.message, ''), a.fired_at, a.last_seen_at, a.resolved_at`,
+		ruleID, StateResolved,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return firstAlert(rows)
+}
+
+// GetOpenAlert returns the firing alert of a rule or nil.
+func (r *PostgresRepository) GetOpenAlert(ctx context.Context, ruleID uuid.UUID) (*Alert, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.rule_id, r.name, a.project_id, a.state, a.value, a.match_count,
+			COALESCE(a.message, ''), a.fired_at, a.last_seen_at, a.resolved_at
+		FROM alerts a
+		JOIN alert_rules r ON r.id = a.rule_id
+		WHERE a.rule_id = $1 AND a.resolved_at IS NULL`,
+		ruleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return firstAlert(rows)
+}
+
+// ListAlerts returns the alert history of a project, newest first.
+func (r *PostgresRepository) ListAlerts(ctx context.Context, projectID uuid.UUID, state string, limit int) ([]*Alert, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.id, a.rule_id, r.name, a.project_id, a.state, a.value, a.match_count,
+			COALESCE(a.message, ''), a.fired_at, a.last_seen_at, a.resolved_at
+		FROM alerts a
+		JOIN alert_rules r ON r.id = a.rule_id
+		WHERE a.project_id = $1 AND ($2 = '' OR a.state = $2)
+		ORDER BY a.fired_at DESC
+		LIMIT $3`,
+		projectID, state, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAlerts(rows)
+}
+
+func scanRules(rows pgx.Rows) ([]*Rule, error) {
+	var rules []*Rule
+	for rows.Next() {
+		rule := &Rule{}
+		var windowSeconds int64
+		err := rows.Scan(
+			&rule.ID, &rule.ProjectID, &rule.Name, &rule.Kind, &rule.Category, &rule.Pattern,
+			&rule.Threshold, &windowSeconds, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rule.Window = time.Duration(windowSeconds) * time.Second
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func scanAlerts(rows pgx.Rows) ([]*Alert, error) {
+	var alerts []*Alert
+	for rows.Next() {
+		a := &Alert{}
+		err := rows.Scan(
+			&a.ID, &a.RuleID, &a.RuleName, &a.ProjectID, &a.State, &a.Value, &a.MatchCount,
+			&a.Message, &a.FiredAt, &a.LastSeenAt, &a.ResolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func firstAlert(rows pgx.Rows) (*Alert, error) {
+	alerts, err := scanAlerts(rows)
+	if err != nil || len(alerts) == 0 {
+		return nil, err
+	}
+	return alerts[0], nil
+}
@@ -0,0 +1,106 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	DirectionSpike = "spike"
+	DirectionDrop  = "drop"
+)
+
+// Anomaly is an hour in which the log volume of a project and category
+// deviated from its baseline.
+type Anomaly struct {
+	ID         uuid.UUID `json:"id"`
+	ProjectID  uuid.UUID `json:"project_id"`
+	Category   string    `json:"category"`
+	Hour       time.Time `json:"hour"`
+	Observed   int64     `json:"observed"`
+	Baseline   float64   `json:"baseline"`
+	Score      float64   `json:"score"`
+	Direction  string    `json:"direction"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Filter narrows down ListAnomalies to hours in [From, To), an empty
+// category matches every category.
+type Filter struct {
+	From     time.Time
+	To       time.Time
+	Category string
+	MinScore float64
+	Limit    int
+}
+
+type Repository interface {
+	Save(ctx context.Context, a *Anomaly) (bool, error)
+	ListAnomalies(ctx context.Context, projectID uuid.UUID, filter Filter) ([]*Anomaly, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// Save stores an anomaly unless the hour was already flagged for the project
+// and category, it reports whether the anomaly is new.
+func (r *PostgresRepository) Save(ctx context.Context, a *Anomaly) (bool, error) {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO anomalies (id, project_id, category, hour, observed, baseline, score, direction)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (project_id, category, hour) DO NOTHING
+		RETURNING detected_at`,
+		a.ID, a.ProjectID, a.Category, a.Hour, a.Observed, a.Baseline, a.Score, a.Direction,
+	).Scan(&a.DetectedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListAnomalies returns the anomalies of a project, newest hour first.
+func (r *PostgresRepository) ListAnomalies(ctx context.Context, projectID uuid.UUID, filter Filter) ([]*Anomaly, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, category, hour, observed, baseline, score, direction, detected_at
+		FROM anomalies
+		WHERE project_id = $1 AND hour >= $2 AND hour < $3
+			AND ($4 = '' OR category = $4) AND ABS(score) >= $5
+		ORDER BY hour DESC, ABS(score) DESC
+		LIMIT $6`,
+		projectID, filter.From, filter.To, filter.Category, filter.MinScore, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*Anomaly
+	for rows.Next() {
+		a := &Anomaly{}
+		err := rows.Scan(&a.ID, &a.ProjectID, &a.Category, &a.Hour, &a.Observed,
+			&a.Baseline, &a.Score, &a.Direction, &a.DetectedAt)
+		if err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
@@ -0,0 +1,106 @@
+package category
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository interface {
+	ListCategories(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	ListAllCategories(ctx context.Context) (map[uuid.UUID][]string, error)
+	SetCategories(ctx context.Context, projectID uuid.UUID, names []string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// ListCategories returns the categories configured for a project, none
+// means the project uses the defaults.
+func (r *PostgresRepository) ListCategories(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT name
+		FROM project_categories
+		WHERE project_id = $1
+		ORDER BY name`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// ListAllCategories returns the categories of every project that configured
+// its own.
+func (r *PostgresRepository) ListAllCategories(ctx context.Context) (map[uuid.UUID][]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT project_id, name FROM project_categories ORDER BY project_id, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make(map[uuid.UUID][]string)
+	for rows.Next() {
+		var projectID uuid.UUID
+		var name string
+		if err := rows.Scan(&projectID, &name); err != nil {
+			return nil, err
+		}
+		categories[projectID] = append(categories[projectID], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// SetCategories replaces the categories of a project, no names returns it
+// to the defaults.
+func (r *PostgresRepository) SetCategories(ctx context.Context, projectID uuid.UUID, names []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_categories WHERE project_id = $1`, projectID); err != nil {
+		return err
+	}
+
+	if len(names) > 0 {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO project_categories (project_id, name)
+			SELECT $1, unnest($2::text[])`,
+			projectID, names,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,99 @@
+package clientidentity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrIdentityTaken = errors.New("client identity belongs to another project")
+
+type Repository interface {
+	ListIdentities(ctx context.Context, projectID uuid.UUID) ([]string, error)
+	SetIdentities(ctx context.Context, projectID uuid.UUID, identities []string) error
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// ListIdentities returns the client certificate identities mapped to a
+// project.
+func (r *PostgresRepository) ListIdentities(ctx context.Context, projectID uuid.UUID) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT identity
+		FROM project_client_identities
+		WHERE project_id = $1
+		ORDER BY identity`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []string
+	for rows.Next() {
+		var identity string
+		if err := rows.Scan(&identity); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// SetIdentities replaces the client certificate identities of a project. It
+// returns ErrIdentityTaken when one of them is mapped to another project.
+func (r *PostgresRepository) SetIdentities(ctx context.Context, projectID uuid.UUID, identities []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var taken string
+	err = tx.QueryRow(ctx, `
+		SELECT identity
+		FROM project_client_identities
+		WHERE identity = ANY($2) AND project_id <> $1
+		LIMIT 1`,
+		projectID, identities,
+	).Scan(&taken)
+	if err == nil {
+		return fmt.Errorf("%w: %q", ErrIdentityTaken, taken)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_client_identities WHERE project_id = $1`, projectID); err != nil {
+		return err
+	}
+
+	if len(identities) > 0 {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO project_client_identities (identity, project_id)
+			SELECT unnest($2::text[]), $1`,
+			projectID, identities,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
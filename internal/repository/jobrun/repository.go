@@ -0,0 +1,133 @@
+package jobrun
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusPartial   = "partial"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// ErrSlotTaken is returned by Start when the scheduled slot already has a
+// run, recorded by this or another replica.
+var ErrSlotTaken = errors.New("scheduled run already recorded")
+
+type Run struct {
+	ID           uuid.UUID     `json:"id"`
+	JobName      string        `json:"job_name"`
+	ScheduledAt  *time.Time    `json:"scheduled_at"`
+	Status       string        `json:"status"`
+	RowsAffected int64         `json:"rows_affected"`
+	Error        string        `json:"error"`
+	ResumeCursor string        `json:"resume_cursor"`
+	StartedAt    time.Time     `json:"started_at"`
+	FinishedAt   *time.Time    `json:"finished_at"`
+	Duration     time.Duration `json:"duration"`
+}
+
+type Repository interface {
+	Start(ctx context.Context, jobName string, scheduledAt *time.Time) (*Run, error)
+	Finish(ctx context.Context, run *Run) error
+	GetLastRun(ctx context.Context, jobName string) (*Run, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// Start records a run. Runs of a scheduled slot are recorded once, Start
+// returns ErrSlotTaken for the slot's later attempts.
+func (r *PostgresRepository) Start(ctx context.Context, jobName string, scheduledAt *time.Time) (*Run, error) {
+	run := &Run{
+		ID:          uuid.New(),
+		JobName:     jobName,
+		ScheduledAt: scheduledAt,
+		Status:      StatusRunning,
+	}
+
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO job_runs (id, job_name, status, scheduled_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_name, scheduled_at) WHERE scheduled_at IS NOT NULL DO NOTHING
+		RETURNING started_at`,
+		run.ID, run.JobName, run.Status, run.ScheduledAt,
+	).Scan(&run.StartedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// Finish stores the final status and statistics of a run.
+func (r *PostgresRepository) Finish(ctx context.Context, run *Run) error {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Duration = now.Sub(run.StartedAt)
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE job_runs
+		SET status = $2, rows_affected = $3, error = NULLIF($4, ''),
+			resume_cursor = NULLIF($5, ''), finished_at = $6, duration_ms = $7
+		WHERE id = $1`,
+		run.ID, run.Status, run.RowsAffected, run.Error,
+		run.ResumeCursor, now, run.Duration.Milliseconds(),
+	)
+	return err
+}
+
+// GetLastRun returns the most recent finished run of a job or nil if it
+// never ran.
+func (r *PostgresRepository) GetLastRun(ctx context.Context, jobName string) (*Run, error) {
+	run := &Run{}
+	var errorText, cursor *string
+	var durationMs *int64
+
+	err := r.db.QueryRow(ctx, `
+		SELECT id, job_name, scheduled_at, status, rows_affected, error, resume_cursor,
+			started_at, finished_at, duration_ms
+		FROM job_runs
+		WHERE job_name = $1 AND status <> $2
+		ORDER BY started_at DESC
+		LIMIT 1`,
+		jobName, StatusRunning,
+	).Scan(
+		&run.ID, &run.JobName, &run.ScheduledAt, &run.Status, &run.RowsAffected, &errorText,
+		&cursor, &run.StartedAt, &run.FinishedAt, &durationMs,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if errorText != nil {
+		run.Error = *errorText
+	}
+	if cursor != nil {
+		run.ResumeCursor = *cursor
+	}
+	if durationMs != nil {
+		run.Duration = time.Duration(*durationMs) * time.Millisecond
+	}
+
+	return run, nil
+}
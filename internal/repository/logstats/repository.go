@@ -0,0 +1,241 @@
+package logstats
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Rollup granularities, minute rows are written at ingestion and compacted
+// into hour and later day rows as they age.
+const (
+	GranularityMinute = "minute"
+	GranularityHour   = "hour"
+	GranularityDay    = "day"
+)
+
+// Count is the usage of one project and category within a minute.
+type Count struct {
+	ProjectID uuid.UUID
+	Category  string
+	Minute    time.Time
+	Logs      int64
+	Bytes     int64
+}
+
+// Point is the usage of one category within a stats bucket.
+type Point struct {
+	Bucket   time.Time
+	Category string
+	Logs     int64
+	Bytes    int64
+}
+
+// HourlyCount is the number of logs of one project and category in an hour.
+type HourlyCount struct {
+	ProjectID uuid.UUID
+	Category  string
+	Hour      time.Time
+	Logs      int64
+}
+
+// ProjectUsage is the usage of a project over a billing period.
+type ProjectUsage struct {
+	ProjectID   uuid.UUID
+	UserID      string
+	AccountType string
+	Logs        int64
+	Bytes       int64
+	Categories  map[string]int64
+}
+
+type Repository interface {
+	AddCounts(ctx context.Context, counts []Count) error
+	GetStats(ctx context.Context, projectID uuid.UUID, from, to time.Time, bucket time.Duration) ([]Point, error)
+	GetUsage(ctx context.Context, from, to time.Time) ([]*ProjectUsage, error)
+	GetHourlyCounts(ctx context.Context, hours []time.Time) ([]HourlyCount, error)
+	Compact(ctx context.Context, from, to string, before time.Time) (int64, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// AddCounts adds the counts to the minute rollups in a single statement.
+func (r *PostgresRepository) AddCounts(ctx context.Context, counts []Count) error {
+	projectIDs := make([]uuid.UUID, len(counts))
+	categories := make([]string, len(counts))
+	minutes := make([]time.Time, len(counts))
+	logs := make([]int64, len(counts))
+	bytes := make([]int64, len(counts))
+	for i, c := range counts {
+		projectIDs[i] = c.ProjectID
+		categories[i] = c.Category
+		minutes[i] = c.Minute
+		logs[i] = c.Logs
+		bytes[i] = c.Bytes
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO log_rollups (project_id, category, bucket, log_count, byte_count, granularity)
+		SELECT p, c, b, l, n, $6
+		FROM unnest($1::uuid[], $2::text[], $3::timestamp[], $4::bigint[], $5::bigint[]) AS t(p, c, b, l, n)
+		ON CONFLICT (project_id, bucket, category, granularity) DO UPDATE
+		SET log_count = log_rollups.log_count + EXCLUDED.log_count,
+			byte_count = log_rollups.byte_count + EXCLUDED.byte_count`,
+		projectIDs, categories, minutes, logs, bytes, GranularityMinute,
+	)
+	return err
+}
+
+// Origin is where stats buckets are aligned to, a Monday so weekly buckets
+// start on Mondays.
+var Origin = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GetStats sums the rollups of a project in [from, to) into buckets aligned
+// to Origin. Compacted rows count towards the bucket their hour or day
+// starts in, so old ranges are only as precise as their granularity.
+func (r *PostgresRepository) GetStats(ctx context.Context, projectID uuid.UUID, from, to time.Time, bucket time.Duration) ([]Point, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT date_bin(make_interval(secs => $4), bucket, $5::timestamp) AS stats_bucket,
+			category, SUM(log_count)::bigint, SUM(byte_count)::bigint
+		FROM log_rollups
+		WHERE project_id = $1 AND bucket >= $2 AND bucket < $3
+		GROUP BY stats_bucket, category
+		ORDER BY stats_bucket, category`,
+		projectID, from, to, bucket.Seconds(), Origin,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []Point
+	for rows.Next() {
+		var p Point
+		if err := rows.Scan(&p.Bucket, &p.Category, &p.Logs, &p.Bytes); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetUsage returns the usage of every project with logs in [from, to),
+// which is what billing exports are built from.
+func (r *PostgresRepository) GetUsage(ctx context.Context, from, to time.Time) ([]*ProjectUsage, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.project_id, COALESCE(u.user_id, ''), COALESCE(u.account_type::text, ''),
+			r.category, SUM(r.log_count)::bigint, SUM(r.byte_count)::bigint
+		FROM log_rollups r
+		LEFT JOIN projects p ON p.id = r.project_id
+		LEFT JOIN users u ON u.id = p.user_id
+		WHERE r.bucket >= $1 AND r.bucket < $2
+		GROUP BY r.project_id, u.user_id, u.account_type, r.category
+		ORDER BY r.project_id, r.category`,
+		from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usages []*ProjectUsage
+	var current *ProjectUsage
+	for rows.Next() {
+		var projectID uuid.UUID
+		var userID, accountType, category string
+		var logs, bytes int64
+		if err := rows.Scan(&projectID, &userID, &accountType, &category, &logs, &bytes); err != nil {
+			return nil, err
+		}
+
+		if current == nil || current.ProjectID != projectID {
+			current = &ProjectUsage{
+				ProjectID:   projectID,
+				UserID:      userID,
+				AccountType: accountType,
+				Categories:  make(map[string]int64),
+			}
+			usages = append(usages, current)
+		}
+		current.Logs += logs
+		current.Bytes += bytes
+		current.Categories[category] += logs
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+// GetHourlyCounts sums the rollups of every project per category for each of
+// the given hours. Hours without logs have no row, and hours already
+// compacted into days are missing altogether.
+func (r *PostgresRepository) GetHourlyCounts(ctx context.Context, hours []time.Time) ([]HourlyCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.project_id, r.category, h.start, SUM(r.log_count)::bigint
+		FROM unnest($1::timestamp[]) AS h(start)
+		JOIN log_rollups r ON r.bucket >= h.start AND r.bucket < h.start + interval '1 hour'
+		WHERE r.granularity IN ($2, $3)
+		GROUP BY r.project_id, r.category, h.start`,
+		hours, GranularityMinute, GranularityHour,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []HourlyCount
+	for rows.Next() {
+		var c HourlyCount
+		if err := rows.Scan(&c.ProjectID, &c.Category, &c.Hour, &c.Logs); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// Compact folds rows of granularity from older than before into rows of
+// granularity to. Rows move in a single statement so usage totals never
+// change, before should be aligned to the target granularity.
+func (r *PostgresRepository) Compact(ctx context.Context, from, to string, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		WITH moved AS (
+			DELETE FROM log_rollups
+			WHERE granularity = $1 AND bucket < $3
+			RETURNING project_id, bucket, category, log_count, byte_count
+		)
+		INSERT INTO log_rollups (project_id, bucket, category, granularity, log_count, byte_count)
+		SELECT project_id, date_trunc($2, bucket), category, $2, SUM(log_count), SUM(byte_count)
+		FROM moved
+		GROUP BY 1, 2, 3
+		ON CONFLICT (project_id, bucket, category, granularity) DO UPDATE
+		SET log_count = log_rollups.log_count + EXCLUDED.log_count,
+			byte_count = log_rollups.byte_count + EXCLUDED.byte_count`,
+		from, to, before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,227 @@
+package pattern
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pattern is a message template of a project, variable tokens are masked
+// with <*>.
+type Pattern struct {
+	ID        string    `json:"id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Template  string    `json:"template"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Count is the number of logs of a pattern within a minute.
+type Count struct {
+	ProjectID uuid.UUID
+	PatternID string
+	Minute    time.Time
+	Logs      int64
+}
+
+// TopPattern is a pattern with its log count and newest messages in a range.
+type TopPattern struct {
+	Pattern
+	Logs    int64
+	Samples []string
+}
+
+type Repository interface {
+	ListPatterns(ctx context.Context, projectID uuid.UUID) ([]*Pattern, error)
+	Save(ctx context.Context, patterns []*Pattern, counts []Count) error
+	TopPatterns(ctx context.Context, projectID uuid.UUID, from, to time.Time, limit, samples int) ([]*TopPattern, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// ListPatterns returns every stored pattern of a project.
+func (r *PostgresRepository) ListPatterns(ctx context.Context, projectID uuid.UUID) ([]*Pattern, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, template, first_seen, last_seen
+		FROM log_patterns
+		WHERE project_id = $1
+		ORDER BY first_seen`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var patterns []*Pattern
+	for rows.Next() {
+		p := &Pattern{}
+		if err := rows.Scan(&p.ID, &p.ProjectID, &p.Template, &p.FirstSeen, &p.LastSeen); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return patterns, nil
+}
+
+// Save upserts the patterns and adds the counts in one transaction, a
+// pattern's template is replaced since it only ever gets more general.
+func (r *PostgresRepository) Save(ctx context.Context, patterns []*Pattern, counts []Count) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, len(patterns))
+	projectIDs := make([]uuid.UUID, len(patterns))
+	templates := make([]string, len(patterns))
+	lastSeen := make([]time.Time, len(patterns))
+	for i, p := range patterns {
+		ids[i] = p.ID
+		projectIDs[i] = p.ProjectID
+		templates[i] = p.Template
+		lastSeen[i] = p.LastSeen
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO log_patterns (id, project_id, template, first_seen, last_seen)
+		SELECT i, p, t, s, s
+		FROM unnest($1::text[], $2::uuid[], $3::text[], $4::timestamp[]) AS u(i, p, t, s)
+		ON CONFLICT (project_id, id) DO UPDATE
+		SET template = EXCLUDED.template,
+			last_seen = GREATEST(log_patterns.last_seen, EXCLUDED.last_seen)`,
+		ids, projectIDs, templates, lastSeen,
+	)
+	if err != nil {
+		return err
+	}
+
+	countProjectIDs := make([]uuid.UUID, len(counts))
+	patternIDs := make([]string, len(counts))
+	minutes := make([]time.Time, len(counts))
+	logs := make([]int64, len(counts))
+	for i, c := range counts {
+		countProjectIDs[i] = c.ProjectID
+		patternIDs[i] = c.PatternID
+		minutes[i] = c.Minute
+		logs[i] = c.Logs
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO log_pattern_counts (project_id, pattern_id, bucket, log_count)
+		SELECT p, i, b, l
+		FROM unnest($1::uuid[], $2::text[], $3::timestamp[], $4::bigint[]) AS u(p, i, b, l)
+		ON CONFLICT (project_id, bucket, pattern_id) DO UPDATE
+		SET log_count = log_pattern_counts.log_count + EXCLUDED.log_count`,
+		countProjectIDs, patternIDs, minutes, logs,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// TopPatterns returns the patterns with the most logs in [from, to) along
+// with up to samples of their newest messages in the range.
+func (r *PostgresRepository) TopPatterns(ctx context.Context, projectID uuid.UUID, from, to time.Time, limit, samples int) ([]*TopPattern, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.id, p.project_id, p.template, p.first_seen, p.last_seen, SUM(c.log_count)::bigint AS logs
+		FROM log_pattern_counts c
+		JOIN log_patterns p ON p.project_id = c.project_id AND p.id = c.pattern_id
+		WHERE c.project_id = $1 AND c.bucket >= $2 AND c.bucket < $3
+		GROUP BY p.id, p.project_id, p.template, p.first_seen, p.last_seen
+		ORDER BY logs DESC, p.id
+		LIMIT $4`,
+		projectID, from, to, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var top []*TopPattern
+	byID := make(map[string]*TopPattern)
+	for rows.Next() {
+		t := &TopPattern{}
+		if err := rows.Scan(&t.ID, &t.ProjectID, &t.Template, &t.FirstSeen, &t.LastSeen, &t.Logs); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		top = append(top, t)
+		byID[t.ID] = t
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(top) == 0 || samples <= 0 {
+		return top, nil
+	}
+
+	ids := make([]string, len(top))
+	for i, t := range top {
+		ids[i] = t.ID
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT u.id, s.message
+		FROM unnest($2::text[]) AS u(id)
+		CROSS JOIN LATERAL (
+			SELECT message
+			FROM logs
+			WHERE project_id = $1 AND pattern_id = u.id AND created_at >= $3 AND created_at < $4
+			ORDER BY created_at DESC
+			LIMIT $5
+		) s`,
+		projectID, ids, from, to, samples,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, message string
+		if err := rows.Scan(&id, &message); err != nil {
+			return nil, err
+		}
+		byID[id].Samples = append(byID[id].Samples, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return top, nil
+}
+
+// Prune deletes counts older than before and the patterns not seen since.
+func (r *PostgresRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	counts, err := r.db.Exec(ctx, `DELETE FROM log_pattern_counts WHERE bucket < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	patterns, err := r.db.Exec(ctx, `DELETE FROM log_patterns WHERE last_seen < $1`, before)
+	if err != nil {
+		return counts.RowsAffected(), err
+	}
+
+	return counts.RowsAffected() + patterns.RowsAffected(), nil
+}
@@ -0,0 +1,219 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	ActionDrop    = "drop"
+	ActionSample  = "sample"
+	ActionRewrite = "rewrite"
+	ActionExtract = "extract"
+	ActionRename  = "rename"
+	ActionTag     = "tag"
+)
+
+var (
+	ErrRuleNotFound    = errors.New("processing rule not found")
+	ErrProjectNotFound = errors.New("project not found")
+)
+
+// Rule is an ingestion processing step of a project. It applies to logs of
+// MatchCategory whose message matches MatchPattern, empty values match
+// every log. Which of the other fields are used depends on the action.
+type Rule struct {
+	ID            uuid.UUID `json:"id"`
+	ProjectID     uuid.UUID `json:"project_id"`
+	Position      int       `json:"position"`
+	Name          string    `json:"name"`
+	Enabled       bool      `json:"enabled"`
+	MatchCategory string    `json:"match_category"`
+	MatchPattern  string    `json:"match_pattern"`
+	Action        string    `json:"action"`
+	SamplePercent float64   `json:"sample_percent"`
+	Pattern       string    `json:"pattern"`
+	Replacement   string    `json:"replacement"`
+	Target        string    `json:"target"`
+	Tags          []string  `json:"tags"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Repository interface {
+	CreateRule(ctx context.Context, rule *Rule) error
+	UpdateRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error
+	ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error)
+	ListAllRules(ctx context.Context) ([]*Rule, error)
+	GetBodyFormat(ctx context.Context, projectID uuid.UUID) (string, error)
+	SetBodyFormat(ctx context.Context, projectID uuid.UUID, format string) error
+	ListBodyFormats(ctx context.Context) (map[uuid.UUID]string, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// CreateRule stores a rule, a position of 0 appends it after the existing
+// rules of the project.
+func (r *PostgresRepository) CreateRule(ctx context.Context, rule *Rule) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO processing_rules (id, project_id, position, name, enabled, match_category, match_pattern,
+			action, sample_percent, pattern, replacement, target, tags)
+		VALUES ($1, $2,
+			CASE WHEN $3 > 0 THEN $3 ELSE (SELECT COALESCE(MAX(position), 0) + 1 FROM processing_rules WHERE project_id = $2) END,
+			$4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING position, created_at, updated_at`,
+		rule.ID, rule.ProjectID, rule.Position, rule.Name, rule.Enabled, rule.MatchCategory, rule.MatchPattern,
+		rule.Action, rule.SamplePercent, rule.Pattern, rule.Replacement, rule.Target, tagsOrEmpty(rule.Tags),
+	).Scan(&rule.Position, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// UpdateRule replaces a rule, a position of 0 keeps its current position.
+func (r *PostgresRepository) UpdateRule(ctx context.Context, rule *Rule) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE processing_rules
+		SET position = CASE WHEN $3 > 0 THEN $3 ELSE position END,
+			name = $4, enabled = $5, match_category = $6, match_pattern = $7, action = $8,
+			sample_percent = $9, pattern = $10, replacement = $11, target = $12, tags = $13,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND project_id = $2
+		RETURNING position, created_at, updated_at`,
+		rule.ID, rule.ProjectID, rule.Position, rule.Name, rule.Enabled, rule.MatchCategory, rule.MatchPattern,
+		rule.Action, rule.SamplePercent, rule.Pattern, rule.Replacement, rule.Target, tagsOrEmpty(rule.Tags),
+	).Scan(&rule.Position, &rule.CreatedAt, &rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrRuleNotFound
+	}
+	return err
+}
+
+func (r *PostgresRepository) DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM processing_rules WHERE project_id = $1 AND id = $2`, projectID, ruleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// ListRules returns the rules of a project in evaluation order.
+func (r *PostgresRepository) ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, position, name, enabled, match_category, match_pattern, action,
+			sample_percent, pattern, replacement, target, tags, created_at, updated_at
+		FROM processing_rules
+		WHERE project_id = $1
+		ORDER BY position, created_at, id`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+// ListAllRules returns the rules of every project in evaluation order.
+func (r *PostgresRepository) ListAllRules(ctx context.Context) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, position, name, enabled, match_category, match_pattern, action,
+			sample_percent, pattern, replacement, target, tags, created_at, updated_at
+		FROM processing_rules
+		ORDER BY project_id, position, created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+func (r *PostgresRepository) GetBodyFormat(ctx context.Context, projectID uuid.UUID) (string, error) {
+	var format string
+	err := r.db.QueryRow(ctx, `SELECT body_format FROM projects WHERE id = $1`, projectID).Scan(&format)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrProjectNotFound
+	}
+	return format, err
+}
+
+func (r *PostgresRepository) SetBodyFormat(ctx context.Context, projectID uuid.UUID, format string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE projects SET body_format = $2 WHERE id = $1`, projectID, format)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// ListBodyFormats returns the body format of every project that parses
+// message bodies.
+func (r *PostgresRepository) ListBodyFormats(ctx context.Context) (map[uuid.UUID]string, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, body_format FROM projects WHERE body_format <> 'none'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	formats := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var projectID uuid.UUID
+		var format string
+		if err := rows.Scan(&projectID, &format); err != nil {
+			return nil, err
+		}
+		formats[projectID] = format
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return formats, nil
+}
+
+func scanRules(rows pgx.Rows) ([]*Rule, error) {
+	var rules []*Rule
+	for rows.Next() {
+		rule := &Rule{}
+		err := rows.Scan(
+			&rule.ID, &rule.ProjectID, &rule.Position, &rule.Name, &rule.Enabled,
+			&rule.MatchCategory, &rule.MatchPattern, &rule.Action, &rule.SamplePercent,
+			&rule.Pattern, &rule.Replacement, &rule.Target, &rule.Tags,
+			&rule.CreatedAt, &rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -6,9 +6,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var ErrProjectNotFound = errors.New("project not found")
+
 type Project struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
@@ -20,6 +23,7 @@ type Project struct {
 type Repository interface {
 	Create(ctx context.Context, name string, userID uuid.UUID, apiKey string) (*Project, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Project, error)
+	GetOwner(ctx context.Context, id uuid.UUID) (string, string, error)
 	GetAllProjects(ctx context.Context) ([]*Project, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -102,6 +106,27 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*Projec
 	return project, nil
 }
 
+// GetOwner returns the user_id and account type of the project owner.
+func (r *PostgresRepository) GetOwner(ctx context.Context, id uuid.UUID) (string, string, error) {
+	var userID, accountType string
+
+	err := r.db.QueryRow(ctx, `
+		SELECT u.user_id, u.account_type
+		FROM projects p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.id = $1`,
+		id,
+	).Scan(&userID, &accountType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", ErrProjectNotFound
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return userID, accountType, nil
+}
+
 func (r *PostgresRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*Project, error) {
 	query := `
 		SELECT id, name, user_id, api_key, created_at 
@@ -138,7 +163,6 @@ func (r *PostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	var ErrProjectNotFound = errors.New("project not found")
 	if result.RowsAffected() == 0 {
 		return ErrProjectNotFound
 	}
@@ -0,0 +1,186 @@
+package redaction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DetectorCustom marks a rule with its own pattern, every other detector
+// names a built-in detector whose defaults the rule overrides.
+const DetectorCustom = "custom"
+
+var ErrRuleNotFound = errors.New("redaction rule not found")
+
+type Rule struct {
+	ID        uuid.UUID `json:"id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Detector  string    `json:"detector"`
+	Name      string    `json:"name"`
+	Pattern   string    `json:"pattern"`
+	Action    string    `json:"action"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Count is the number of values a detector redacted with an action on a day.
+type Count struct {
+	ProjectID uuid.UUID
+	Detector  string
+	Action    string
+	Day       time.Time
+	Count     int64
+}
+
+type Repository interface {
+	SaveRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error
+	ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error)
+	ListAllRules(ctx context.Context) ([]*Rule, error)
+	AddCounts(ctx context.Context, counts []Count) error
+	GetCounts(ctx context.Context, projectID uuid.UUID, from, to time.Time) ([]Count, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// SaveRule creates a rule. A project has one override per built-in
+// detector, saving another replaces it and keeps the original id.
+func (r *PostgresRepository) SaveRule(ctx context.Context, rule *Rule) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO redaction_rules (id, project_id, detector, name, pattern, action, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (project_id, detector) WHERE detector <> 'custom' DO UPDATE
+		SET name = EXCLUDED.name, action = EXCLUDED.action, enabled = EXCLUDED.enabled
+		RETURNING id, created_at`,
+		rule.ID, rule.ProjectID, rule.Detector, rule.Name, rule.Pattern, rule.Action, rule.Enabled,
+	).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (r *PostgresRepository) DeleteRule(ctx context.Context, projectID, ruleID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM redaction_rules WHERE project_id = $1 AND id = $2`, projectID, ruleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListRules(ctx context.Context, projectID uuid.UUID) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, detector, name, pattern, action, enabled, created_at
+		FROM redaction_rules
+		WHERE project_id = $1
+		ORDER BY created_at, id`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+// ListAllRules returns the rules of every project, which is what the
+// redactor caches.
+func (r *PostgresRepository) ListAllRules(ctx context.Context) ([]*Rule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, detector, name, pattern, action, enabled, created_at
+		FROM redaction_rules
+		ORDER BY project_id, created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRules(rows)
+}
+
+// AddCounts adds the counts to the daily redaction counters.
+func (r *PostgresRepository) AddCounts(ctx context.Context, counts []Count) error {
+	projectIDs := make([]uuid.UUID, len(counts))
+	detectors := make([]string, len(counts))
+	actions := make([]string, len(counts))
+	days := make([]time.Time, len(counts))
+	values := make([]int64, len(counts))
+	for i, c := range counts {
+		projectIDs[i] = c.ProjectID
+		detectors[i] = c.Detector
+		actions[i] = c.Action
+		days[i] = c.Day
+		values[i] = c.Count
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO redaction_counts (project_id, detector, action, day, redactions)
+		SELECT p, d, a, t::date, n
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::timestamp[], $5::bigint[]) AS u(p, d, a, t, n)
+		ON CONFLICT (project_id, day, detector, action) DO UPDATE
+		SET redactions = redaction_counts.redactions + EXCLUDED.redactions`,
+		projectIDs, detectors, actions, days, values,
+	)
+	return err
+}
+
+// GetCounts sums the counters of a project per detector and action over the
+// days in [from, to).
+func (r *PostgresRepository) GetCounts(ctx context.Context, projectID uuid.UUID, from, to time.Time) ([]Count, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT detector, action, SUM(redactions)::bigint
+		FROM redaction_counts
+		WHERE project_id = $1 AND day >= $2::date AND day < $3::date
+		GROUP BY detector, action
+		ORDER BY detector, action`,
+		projectID, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []Count
+	for rows.Next() {
+		c := Count{ProjectID: projectID}
+		if err := rows.Scan(&c.Detector, &c.Action, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func scanRules(rows pgx.Rows) ([]*Rule, error) {
+	var rules []*Rule
+	for rows.Next() {
+		rule := &Rule{}
+		err := rows.Scan(&rule.ID, &rule.ProjectID, &rule.Detector, &rule.Name,
+			&rule.Pattern, &rule.Action, &rule.Enabled, &rule.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
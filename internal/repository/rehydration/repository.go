@@ -0,0 +1,161 @@
+package rehydration
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	StatusRestoring = "restoring"
+	StatusReady     = "ready"
+	StatusFailed    = "failed"
+	StatusExpired   = "expired"
+)
+
+// ErrOverlap is returned by Create when the project already has a restoring
+// or ready rehydration overlapping the range, restoring it again would
+// duplicate its logs.
+var ErrOverlap = errors.New("range overlaps an active rehydration")
+
+type Rehydration struct {
+	ID           uuid.UUID `json:"id"`
+	ProjectID    uuid.UUID `json:"project_id"`
+	RangeStart   time.Time `json:"range_start"`
+	RangeEnd     time.Time `json:"range_end"`
+	Status       string    `json:"status"`
+	RowsRestored int64     `json:"rows_restored"`
+	Error        string    `json:"error"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type Repository interface {
+	Create(ctx context.Context, r *Rehydration) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, rowsRestored int64, errorText string) error
+	ListByProject(ctx context.Context, projectID uuid.UUID) ([]*Rehydration, error)
+	ListExpired(ctx context.Context, now time.Time) ([]*Rehydration, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// Create records a rehydration unless an unexpired restoring or ready
+// rehydration of the project overlaps its range. Creates for the same project
+// are serialized so concurrent requests can't both pass the check.
+func (r *PostgresRepository) Create(ctx context.Context, rehydration *Rehydration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('logsentinel.rehydration'), hashtext($1::text))`, rehydration.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	var overlaps bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM rehydrations
+			WHERE project_id = $1 AND status IN ($2, $3) AND expires_at > CURRENT_TIMESTAMP
+				AND range_start < $5 AND range_end > $4
+		)`,
+		rehydration.ProjectID, StatusRestoring, StatusReady, rehydration.RangeStart, rehydration.RangeEnd,
+	).Scan(&overlaps)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return ErrOverlap
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO rehydrations (id, project_id, range_start, range_end, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at`,
+		rehydration.ID, rehydration.ProjectID, rehydration.RangeStart, rehydration.RangeEnd,
+		rehydration.Status, rehydration.ExpiresAt,
+	).Scan(&rehydration.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, rowsRestored int64, errorText string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE rehydrations
+		SET status = $2, rows_restored = $3, error = NULLIF($4, '')
+		WHERE id = $1`,
+		id, status, rowsRestored, errorText,
+	)
+	return err
+}
+
+func (r *PostgresRepository) ListByProject(ctx context.Context, projectID uuid.UUID) ([]*Rehydration, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, range_start, range_end, status, rows_restored,
+			COALESCE(error, ''), created_at, expires_at
+		FROM rehydrations
+		WHERE project_id = $1
+		ORDER BY created_at DESC`,
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRehydrations(rows)
+}
+
+// ListExpired returns rehydrations past their expiry whose data still exists.
+func (r *PostgresRepository) ListExpired(ctx context.Context, now time.Time) ([]*Rehydration, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, range_start, range_end, status, rows_restored,
+			COALESCE(error, ''), created_at, expires_at
+		FROM rehydrations
+		WHERE expires_at <= $1 AND status <> $2
+		ORDER BY expires_at`,
+		now, StatusExpired,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRehydrations(rows)
+}
+
+func scanRehydrations(rows pgx.Rows) ([]*Rehydration, error) {
+	var rehydrations []*Rehydration
+	for rows.Next() {
+		r := &Rehydration{}
+		err := rows.Scan(
+			&r.ID, &r.ProjectID, &r.RangeStart, &r.RangeEnd, &r.Status,
+			&r.RowsRestored, &r.Error, &r.CreatedAt, &r.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rehydrations = append(rehydrations, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rehydrations, nil
+}
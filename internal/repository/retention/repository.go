@@ -0,0 +1,265 @@
+package retention
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AllCategories is the category value of a policy that applies to every
+// category without a more specific rule.
+const AllCategories = ""
+
+// Policy keeps logs of Category for RetentionDays, 0 keeps them forever.
+type Policy struct {
+	Category      string `json:"category"`
+	RetentionDays int    `json:"retention_days"`
+}
+
+// ProjectRetention is the effective retention of a project after its
+// overrides are applied on top of its plan.
+type ProjectRetention struct {
+	ProjectID   uuid.UUID
+	AccountType string
+	DefaultDays int
+	Categories  map[string]int
+}
+
+type Repository interface {
+	GetPlanPolicies(ctx context.Context, accountType string) ([]Policy, error)
+	GetProjectPolicies(ctx context.Context, projectID uuid.UUID) ([]Policy, error)
+	ReplaceProjectPolicies(ctx context.Context, projectID uuid.UUID, policies []Policy) error
+	GetAllProjectRetention(ctx context.Context) ([]*ProjectRetention, error)
+}
+
+type PostgresRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// MaxDays returns the longest retention the plan grants, project overrides
+// can't keep logs longer. It returns 0 when the plan keeps some logs
+// forever, overrides are unbounded then.
+func MaxDays(plan []Policy) int {
+	max := 0
+	for _, p := range plan {
+		if p.RetentionDays == 0 {
+			return 0
+		}
+		if p.RetentionDays > max {
+			max = p.RetentionDays
+		}
+	}
+	return max
+}
+
+// Allowed reports whether a project override of days stays within the plan
+// maximum max, see MaxDays.
+func Allowed(days, max int) bool {
+	return max == 0 || (days > 0 && days <= max)
+}
+
+// clamp bounds an override to the plan maximum, so overrides saved before
+// a plan change can't outlive it.
+func clamp(days, max int) int {
+	if Allowed(days, max) {
+		return days
+	}
+	return max
+}
+
+// Resolve merges plan and project policies. A project default replaces the
+// whole plan policy, project category rules then win over anything else.
+// Project rules are bounded by the plan maximum.
+func Resolve(projectID uuid.UUID, accountType string, plan, project []Policy) *ProjectRetention {
+	retention := &ProjectRetention{
+		ProjectID:   projectID,
+		AccountType: accountType,
+		Categories:  make(map[string]int),
+	}
+
+	for _, p := range plan {
+		if p.Category == AllCategories {
+			retention.DefaultDays = p.RetentionDays
+		} else {
+			retention.Categories[p.Category] = p.RetentionDays
+		}
+	}
+
+	max := MaxDays(plan)
+	for _, p := range project {
+		if p.Category == AllCategories {
+			retention.DefaultDays = clamp(p.RetentionDays, max)
+			retention.Categories = make(map[string]int)
+		}
+	}
+	for _, p := range project {
+		if p.Category != AllCategories {
+			retention.Categories[p.Category] = clamp(p.RetentionDays, max)
+		}
+	}
+
+	return retention
+}
+
+// Policies flattens the effective retention back into a sorted policy list.
+func (r *ProjectRetention) Policies() []Policy {
+	policies := []Policy{{Category: AllCategories, RetentionDays: r.DefaultDays}}
+	for category, days := range r.Categories {
+		policies = append(policies, Policy{Category: category, RetentionDays: days})
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Category < policies[j].Category
+	})
+	return policies
+}
+
+func (r *PostgresRepository) GetPlanPolicies(ctx context.Context, accountType string) ([]Policy, error) {
+	query := `
+		SELECT category, retention_days
+		FROM plan_retention_policies
+		WHERE account_type = $1
+		ORDER BY category
+	`
+
+	rows, err := r.db.Query(ctx, query, accountType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPolicies(rows)
+}
+
+func (r *PostgresRepository) GetProjectPolicies(ctx context.Context, projectID uuid.UUID) ([]Policy, error) {
+	query := `
+		SELECT category, retention_days
+		FROM project_retention_policies
+		WHERE project_id = $1
+		ORDER BY category
+	`
+
+	rows, err := r.db.Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPolicies(rows)
+}
+
+func (r *PostgresRepository) ReplaceProjectPolicies(ctx context.Context, projectID uuid.UUID, policies []Policy) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_retention_policies WHERE project_id = $1`, projectID); err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO project_retention_policies (project_id, category, retention_days)
+			VALUES ($1, $2, $3)`,
+			projectID, p.Category, p.RetentionDays,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAllProjectRetention resolves the effective retention of every project.
+func (r *PostgresRepository) GetAllProjectRetention(ctx context.Context) ([]*ProjectRetention, error) {
+	planPolicies := make(map[string][]Policy)
+	rows, err := r.db.Query(ctx, `SELECT account_type, category, retention_days FROM plan_retention_policies`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var accountType string
+		var p Policy
+		if err := rows.Scan(&accountType, &p.Category, &p.RetentionDays); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		planPolicies[accountType] = append(planPolicies[accountType], p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	projectPolicies := make(map[uuid.UUID][]Policy)
+	rows, err = r.db.Query(ctx, `SELECT project_id, category, retention_days FROM project_retention_policies`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var projectID uuid.UUID
+		var p Policy
+		if err := rows.Scan(&projectID, &p.Category, &p.RetentionDays); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		projectPolicies[projectID] = append(projectPolicies[projectID], p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT p.id, u.account_type
+		FROM projects p
+		JOIN users u ON u.id = p.user_id
+		ORDER BY p.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*ProjectRetention
+	for rows.Next() {
+		var projectID uuid.UUID
+		var accountType string
+		if err := rows.Scan(&projectID, &accountType); err != nil {
+			return nil, err
+		}
+		result = append(result, Resolve(projectID, accountType, planPolicies[accountType], projectPolicies[projectID]))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanPolicies(rows pgx.Rows) ([]Policy, error) {
+	var policies []Policy
+	for rows.Next() {
+		var p Policy
+		if err := rows.Scan(&p.Category, &p.RetentionDays); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
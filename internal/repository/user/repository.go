@@ -187,23 +187,32 @@ func (r *PostgresRepository) Delete(ctx context.Context, userID string) error {
 	return nil
 }
 
+// GetQuota reads log usage from the minute rollups rather than counting
+// logs. LogCountLastMinute covers the last full minute and LastLogTime has
+// minute precision, looking back one day at most.
 func (r *PostgresRepository) GetQuota(ctx context.Context, userID string) (*UserQuota, error) {
 	quota := &UserQuota{}
+	lastMinute := time.Now().UTC().Truncate(time.Minute).Add(-time.Minute)
 	query := `
-        WITH stats AS (
-            SELECT 
-                COUNT(DISTINCT p.id) as project_count,
-                COUNT(l.*) FILTER (WHERE l.created_at > NOW() - INTERVAL '1 minute') as log_count,
-                MAX(l.created_at) as last_log_time
-            FROM users u
-            LEFT JOIN projects p ON p.user_id = u.id
-            LEFT JOIN logs l ON l.project_id = p.id
-            WHERE u.user_id = $1
-        )
-        SELECT project_count, log_count, last_log_time FROM stats
+        SELECT
+            (SELECT COUNT(*) FROM projects p WHERE p.user_id = u.id),
+            COALESCE((
+                SELECT SUM(r.log_count)
+                FROM log_rollups r
+                JOIN projects p ON p.id = r.project_id
+                WHERE p.user_id = u.id AND r.granularity = 'minute' AND r.bucket = $2
+            ), 0)::bigint,
+            (
+                SELECT MAX(r.bucket)
+                FROM log_rollups r
+                JOIN projects p ON p.id = r.project_id
+                WHERE p.user_id = u.id AND r.bucket >= $3
+            )
+        FROM users u
+        WHERE u.user_id = $1
     `
 
-	err := r.db.QueryRow(ctx, query, userID).Scan(
+	err := r.db.QueryRow(ctx, query, userID, lastMinute, lastMinute.Add(-24*time.Hour)).Scan(
 		&quota.ProjectCount,
 		&quota.LogCountLastMinute,
 		&quota.LastLogTime,
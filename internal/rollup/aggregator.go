@@ -0,0 +1,187 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+// defaultFlushInterval is how often counted logs are written to the rollups
+const defaultFlushInterval = 10 * time.Second
+
+const compactionJobName = "rollup_compaction"
+
+type Config struct {
+	// MinuteRetention is how long minute rows are kept before they are
+	// folded into hour rows
+	MinuteRetention time.Duration
+	// HourRetention is how long hour rows are kept before they are folded
+	// into day rows
+	HourRetention time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MinuteRetention: 48 * time.Hour,
+		HourRetention:   31 * 24 * time.Hour,
+	}
+}
+
+type usage struct {
+	logs  int64
+	bytes int64
+}
+
+type key struct {
+	projectID uuid.UUID
+	category  string
+	minute    time.Time
+}
+
+// Aggregator counts ingested logs and bytes per project, category and
+// minute in memory and adds them to the rollup table periodically, so
+// ingestion costs a map update instead of a database write. Counts that
+// fail to flush are kept for the next attempt.
+type Aggregator struct {
+	repo     logstats.Repository
+	config   Config
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[key]usage
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+func NewAggregator(repo logstats.Repository, config Config) *Aggregator {
+	return &Aggregator{
+		repo:     repo,
+		config:   config,
+		interval: defaultFlushInterval,
+		pending:  make(map[key]usage),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// RegisterJobs adds the hourly compaction of aged rollups.
+func (a *Aggregator) RegisterJobs(sched *scheduler.Scheduler) error {
+	return sched.Register(scheduler.Job{
+		Name:     compactionJobName,
+		Schedule: "15 * * * *",
+		Timeout:  10 * time.Minute,
+		Jitter:   time.Minute,
+		Run:      a.compact,
+	})
+}
+
+// Start flushes the counts every interval until Stop is called.
+func (a *Aggregator) Start() {
+	go func() {
+		defer close(a.done)
+
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				a.flush(context.Background())
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic flush and writes the remaining counts.
+func (a *Aggregator) Stop(ctx context.Context) error {
+	close(a.quit)
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.flush(ctx)
+}
+
+// Record counts a stored log of the given size.
+func (a *Aggregator) Record(projectID uuid.UUID, category string, bytes int, createdAt time.Time) {
+	k := key{projectID: projectID, category: category, minute: createdAt.Truncate(time.Minute)}
+
+	a.mu.Lock()
+	u := a.pending[k]
+	u.logs++
+	u.bytes += int64(bytes)
+	a.pending[k] = u
+	a.mu.Unlock()
+}
+
+func (a *Aggregator) flush(ctx context.Context) error {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = make(map[key]usage)
+	a.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	counts := make([]logstats.Count, 0, len(pending))
+	for k, u := range pending {
+		counts = append(counts, logstats.Count{
+			ProjectID: k.projectID,
+			Category:  k.category,
+			Minute:    k.minute,
+			Logs:      u.logs,
+			Bytes:     u.bytes,
+		})
+	}
+
+	if err := a.repo.AddCounts(ctx, counts); err != nil {
+		slog.Error("Error flushing log rollups, retrying next flush", "err", err)
+
+		a.mu.Lock()
+		for k, u := range pending {
+			merged := a.pending[k]
+			merged.logs += u.logs
+			merged.bytes += u.bytes
+			a.pending[k] = merged
+		}
+		a.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// compact folds minute rows into hours and hour rows into days once they
+// are older than their retention. Cutoffs are aligned so a compacted hour or
+// day is always complete.
+func (a *Aggregator) compact(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+	now := time.Now().UTC()
+
+	hourCutoff := now.Add(-a.config.MinuteRetention).Truncate(time.Hour)
+	moved, err := a.repo.Compact(ctx, logstats.GranularityMinute, logstats.GranularityHour, hourCutoff)
+	if err != nil {
+		return fmt.Errorf("compacting minute rollups: %w", err)
+	}
+	run.RowsAffected += moved
+
+	dayCutoff := now.Add(-a.config.HourRetention).Truncate(24 * time.Hour)
+	moved, err = a.repo.Compact(ctx, logstats.GranularityHour, logstats.GranularityDay, dayCutoff)
+	if err != nil {
+		return fmt.Errorf("compacting hour rollups: %w", err)
+	}
+	run.RowsAffected += moved
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/robfig/cron/v3"
+
+	"github.com/AjayShukla007/logsentinel/internal/metrics"
+	"github.com/AjayShukla007/logsentinel/internal/repository/jobrun"
+)
+
+// lockNamespace is the first key of every job advisory lock so they can't
+// collide with locks taken by other parts of the system.
+const lockNamespace = "logsentinel.scheduler"
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobExists   = errors.New("job already registered")
+
+	// ErrIncomplete is returned by a job that stopped early but made
+	// progress, for example because it ran out of its time budget. The run
+	// is recorded as partial instead of failed.
+	ErrIncomplete = errors.New("job stopped before finishing its work")
+)
+
+// Job is a unit of background work run on a cron schedule.
+type Job struct {
+	Name string
+	// Schedule is a standard five field cron expression or a descriptor
+	// like @hourly or @every 10m
+	Schedule string
+	// Timeout bounds a single run, zero means no timeout
+	Timeout time.Duration
+	// Jitter delays every run by a random duration up to this value so
+	// replicas and jobs don't all wake up at the same instant
+	Jitter time.Duration
+	// RunOnStart runs the job once as soon as the scheduler starts
+	RunOnStart bool
+	Run        func(ctx context.Context) error
+}
+
+// JobStatus describes a registered job for the admin API.
+type JobStatus struct {
+	Name     string
+	Schedule string
+	Running  bool
+	NextRun  time.Time
+	LastRun  *jobrun.Run
+}
+
+type entry struct {
+	job      Job
+	schedule cron.Schedule
+	trigger  chan struct{}
+
+	mu      sync.Mutex
+	next    time.Time
+	running bool
+}
+
+type Scheduler struct {
+	db         *pgxpool.Pool
+	jobRunRepo jobrun.Repository
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func New(db *pgxpool.Pool, jobRunRepo jobrun.Repository) *Scheduler {
+	return &Scheduler{
+		db:         db,
+		jobRunRepo: jobRunRepo,
+		entries:    make(map[string]*entry),
+	}
+}
+
+// Register adds a job, it must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", job.Schedule, job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[job.Name]; exists {
+		return ErrJobExists
+	}
+	s.entries[job.Name] = &entry{
+		job:      job,
+		schedule: schedule,
+		trigger:  make(chan struct{}, 1),
+	}
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancel = cancel
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.loop(ctx, e)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Trigger queues an immediate run of a job. A trigger arriving while the job
+// is already queued is a no-op.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	select {
+	case e.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Jobs lists every registered job with its next and last run. Last runs come
+// from the job_runs table so they reflect whichever replica ran the job.
+func (s *Scheduler) Jobs(ctx context.Context) ([]JobStatus, error) {
+	s.mu.Lock()
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].job.Name < entries[j].job.Name
+	})
+
+	statuses := make([]JobStatus, 0, len(entries))
+	for _, e := range entries {
+		lastRun, err := s.jobRunRepo.GetLastRun(ctx, e.job.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+		status := JobStatus{
+			Name:     e.job.Name,
+			Schedule: e.job.Schedule,
+			Running:  e.running,
+			NextRun:  e.next,
+			LastRun:  lastRun,
+		}
+		e.mu.Unlock()
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.wg.Done()
+
+	if e.job.RunOnStart {
+		s.execute(ctx, e, nil)
+	}
+
+	for {
+		// replicas agree on the slot, only their jitter differs
+		slot := e.schedule.Next(time.Now())
+		next := slot
+		if e.job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(e.job.Jitter))))
+		}
+
+		e.mu.Lock()
+		e.next = next
+		e.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.execute(ctx, e, &slot)
+		case <-e.trigger:
+			timer.Stop()
+			s.execute(ctx, e, nil)
+		}
+	}
+}
+
+// execute runs a job if this replica wins its advisory lock. The lock is
+// held on a dedicated connection for the whole run, other replicas skip the
+// run instead of waiting for it. A scheduled slot runs once: a replica whose
+// jittered timer fires after another replica finished the slot finds its
+// run recorded and skips it. Runs without a slot, triggered or on start,
+// only rely on the lock.
+func (s *Scheduler) execute(ctx context.Context, e *entry, slot *time.Time) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Unable to acquire connection for job", "job", e.job.Name, "err", err)
+		}
+		return
+	}
+	defer conn.Release()
+
+	var locked bool
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1), hashtext($2))`, lockNamespace, e.job.Name).Scan(&locked)
+	if err != nil {
+		slog.Error("Unable to take job leader lock", "job", e.job.Name, "err", err)
+		return
+	}
+	if !locked {
+		slog.Debug("Job running on another replica, skipping", "job", e.job.Name)
+		metrics.JobRuns.WithLabelValues(e.job.Name, "skipped").Inc()
+		return
+	}
+	defer func() {
+		// unlock even if the job context was cancelled during shutdown
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock(hashtext($1), hashtext($2))`, lockNamespace, e.job.Name); err != nil {
+			slog.Error("Unable to release job leader lock", "job", e.job.Name, "err", err)
+		}
+	}()
+
+	run, err := s.jobRunRepo.Start(ctx, e.job.Name, slot)
+	if errors.Is(err, jobrun.ErrSlotTaken) {
+		slog.Debug("Scheduled run already handled by another replica, skipping", "job", e.job.Name, "slot", slot)
+		metrics.JobRuns.WithLabelValues(e.job.Name, "skipped").Inc()
+		return
+	}
+	if err != nil {
+		slog.Error("Unable to record job run", "job", e.job.Name, "err", err)
+		return
+	}
+
+	e.mu.Lock()
+	e.running = true
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.running = false
+		e.mu.Unlock()
+	}()
+
+	runCtx := withRun(ctx, run)
+	if e.job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, e.job.Timeout)
+		defer cancel()
+	}
+
+	err = e.job.Run(runCtx)
+	switch {
+	case err == nil:
+		run.Status = jobrun.StatusCompleted
+	case errors.Is(err, ErrIncomplete):
+		run.Status = jobrun.StatusPartial
+	case ctx.Err() != nil:
+		run.Status = jobrun.StatusCancelled
+	default:
+		run.Status = jobrun.StatusFailed
+		run.Error = err.Error()
+	}
+
+	finishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.jobRunRepo.Finish(finishCtx, run); err != nil {
+		slog.Error("Unable to record job run result", "job", e.job.Name, "err", err)
+	}
+
+	metrics.JobRuns.WithLabelValues(e.job.Name, run.Status).Inc()
+	metrics.JobDuration.WithLabelValues(e.job.Name).Observe(run.Duration.Seconds())
+	slog.Info("Job finished", "job", e.job.Name, "status", run.Status, "duration", run.Duration.Round(time.Millisecond), "rows_affected", run.RowsAffected)
+}
+
+type runKey struct{}
+
+func withRun(ctx context.Context, run *jobrun.Run) context.Context {
+	return context.WithValue(ctx, runKey{}, run)
+}
+
+// RunFromContext returns the job run being executed so a job can report the
+// rows it affected and where to resume. Outside of a scheduled run it
+// returns a throwaway run so callers never need a nil check.
+func RunFromContext(ctx context.Context) *jobrun.Run {
+	if run, ok := ctx.Value(runKey{}).(*jobrun.Run); ok {
+		return run
+	}
+	return &jobrun.Run{}
+}
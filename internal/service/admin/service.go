@@ -0,0 +1,288 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/rehydrate"
+	"github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	"github.com/AjayShukla007/logsentinel/internal/repository/rehydration"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+// adminTokenHeader carries the admin token, "authorization: Bearer <token>"
+// is accepted as well.
+const adminTokenHeader = "x-admin-token"
+
+type AdminService struct {
+	pb.UnimplementedAdminServiceServer
+	scheduler  *scheduler.Scheduler
+	rehydrator *rehydrate.Rehydrator
+	notifier   *notify.Dispatcher
+	usage      logstats.Repository
+	token      string
+}
+
+// NewAdminService creates the admin API. With an empty token every call is
+// rejected so the API is never accidentally left open.
+func NewAdminService(sched *scheduler.Scheduler, rehydrator *rehydrate.Rehydrator, notifier *notify.Dispatcher, usage logstats.Repository, token string) *AdminService {
+	return &AdminService{
+		scheduler:  sched,
+		rehydrator: rehydrator,
+		notifier:   notifier,
+		usage:      usage,
+		token:      token,
+	}
+}
+
+func (s *AdminService) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.scheduler.Jobs(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list jobs: %v", err)
+	}
+
+	resp := &pb.ListJobsResponse{}
+	for _, job := range jobs {
+		pbJob := &pb.Job{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			Running:  job.Running,
+		}
+		if !job.NextRun.IsZero() {
+			pbJob.NextRun = job.NextRun.Format(time.RFC3339)
+		}
+		if run := job.LastRun; run != nil {
+			pbJob.LastRun = &pb.JobRun{
+				Status:       run.Status,
+				StartedAt:    run.StartedAt.Format(time.RFC3339),
+				DurationMs:   run.Duration.Milliseconds(),
+				RowsAffected: run.RowsAffected,
+				Error:        run.Error,
+			}
+			if run.FinishedAt != nil {
+				pbJob.LastRun.FinishedAt = run.FinishedAt.Format(time.RFC3339)
+			}
+		}
+		resp.Jobs = append(resp.Jobs, pbJob)
+	}
+
+	return resp, nil
+}
+
+func (s *AdminService) TriggerJob(ctx context.Context, req *pb.TriggerJobRequest) (*pb.TriggerJobResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	err := s.scheduler.Trigger(req.Name)
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		return nil, status.Errorf(codes.NotFound, "job %q not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to trigger job: %v", err)
+	}
+
+	return &pb.TriggerJobResponse{
+		Success: true,
+		Message: "Job queued, it runs on the replica holding its lock",
+	}, nil
+}
+
+func (s *AdminService) RehydrateLogs(ctx context.Context, req *pb.RehydrateLogsRequest) (*pb.Rehydration, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	if req.TtlHours < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl_hours must not be negative")
+	}
+
+	result, err := s.rehydrator.Rehydrate(ctx, projectID, from, to, time.Duration(req.TtlHours)*time.Hour)
+	switch {
+	case errors.Is(err, rehydrate.ErrArchiveDisabled):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, rehydrate.ErrTooManyRows):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, rehydration.ErrOverlap):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to rehydrate logs: %v", err)
+	}
+
+	return toRehydration(result), nil
+}
+
+func (s *AdminService) ListRehydrations(ctx context.Context, req *pb.ListRehydrationsRequest) (*pb.ListRehydrationsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	rehydrations, err := s.rehydrator.List(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list rehydrations: %v", err)
+	}
+
+	resp := &pb.ListRehydrationsResponse{}
+	for _, r := range rehydrations {
+		resp.Rehydrations = append(resp.Rehydrations, toRehydration(r))
+	}
+	return resp, nil
+}
+
+// TestNotification sends a test event synchronously so channel
+// configuration problems show up in the response instead of the logs.
+func (s *AdminService) TestNotification(ctx context.Context, req *pb.TestNotificationRequest) (*pb.TestNotificationResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	message := req.Message
+	if message == "" {
+		message = "Test notification from LogSentinel"
+	}
+
+	results, err := s.notifier.Test(ctx, req.Channel, notify.Event{
+		Kind:     notify.KindTest,
+		Severity: notify.SeverityInfo,
+		Title:    message,
+		Time:     time.Now().UTC(),
+	})
+	if errors.Is(err, notify.ErrChannelNotFound) {
+		if req.Channel == "" {
+			return nil, status.Error(codes.FailedPrecondition, "no notification channels are configured")
+		}
+		return nil, status.Errorf(codes.NotFound, "notification channel %q not found", req.Channel)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to send test notification: %v", err)
+	}
+
+	resp := &pb.TestNotificationResponse{}
+	for _, name := range s.notifier.Channels() {
+		sendErr, ok := results[name]
+		if !ok {
+			continue
+		}
+		result := &pb.NotificationResult{Channel: name, Success: sendErr == nil}
+		if sendErr != nil {
+			result.Error = sendErr.Error()
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// ExportUsage reports the logs and bytes every project ingested in a period,
+// read from the rollups so it stays cheap for any range. Periods should be
+// aligned to whole days once the rollups of the range are compacted.
+func (s *AdminService) ExportUsage(ctx context.Context, req *pb.ExportUsageRequest) (*pb.UsageReport, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	usages, err := s.usage.GetUsage(ctx, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read usage: %v", err)
+	}
+
+	resp := &pb.UsageReport{
+		From: from.UTC().Format(time.RFC3339),
+		To:   to.UTC().Format(time.RFC3339),
+	}
+	for _, u := range usages {
+		resp.Projects = append(resp.Projects, &pb.ProjectUsage{
+			ProjectId:   u.ProjectID.String(),
+			UserId:      u.UserID,
+			AccountType: u.AccountType,
+			Logs:        u.Logs,
+			Bytes:       u.Bytes,
+			Categories:  u.Categories,
+		})
+	}
+	return resp, nil
+}
+
+func toRehydration(r *rehydration.Rehydration) *pb.Rehydration {
+	return &pb.Rehydration{
+		Id:           r.ID.String(),
+		ProjectId:    r.ProjectID.String(),
+		From:         r.RangeStart.Format(time.RFC3339),
+		To:           r.RangeEnd.Format(time.RFC3339),
+		Status:       r.Status,
+		RowsRestored: r.RowsRestored,
+		Error:        r.Error,
+		CreatedAt:    r.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:    r.ExpiresAt.Format(time.RFC3339),
+		Partition:    rehydrate.PartitionName(r.ID),
+	}
+}
+
+func (s *AdminService) authorize(ctx context.Context) error {
+	if s.token == "" {
+		return status.Error(codes.PermissionDenied, "admin API is disabled, set ADMIN_TOKEN to enable it")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get(adminTokenHeader); len(values) > 0 {
+		token = values[0]
+	} else if values := md.Get("authorization"); len(values) > 0 {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/alerting"
+	"github.com/AjayShukla007/logsentinel/internal/repository/alert"
+	"github.com/AjayShukla007/logsentinel/internal/repository/anomaly"
+	"github.com/AjayShukla007/logsentinel/internal/service/authz"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+const (
+	defaultAlertsLimit = 50
+	maxAlertsLimit     = 500
+)
+
+// defaultAnomalyRange is how far back ListAnomalies looks without a from
+const defaultAnomalyRange = 7 * 24 * time.Hour
+
+type AlertService struct {
+	pb.UnimplementedAlertServiceServer
+	repo        alert.Repository
+	owners      *authz.ProjectOwners
+	anomalyRepo anomaly.Repository
+	engine      *alerting.Engine
+}
+
+func NewAlertService(repo alert.Repository, owners *authz.ProjectOwners, anomalyRepo anomaly.Repository, engine *alerting.Engine) *AlertService {
+	return &AlertService{
+		repo:        repo,
+		owners:      owners,
+		anomalyRepo: anomalyRepo,
+		engine:      engine,
+	}
+}
+
+func (s *AlertService) CreateAlertRule(ctx context.Context, req *pb.SaveAlertRuleRequest) (*pb.AlertRule, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	projectID, _, err := s.owners.Authorize(ctx, req.Rule.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := toRule(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = uuid.New()
+	rule.ProjectID = projectID
+
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create alert rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return toAlertRule(rule), nil
+}
+
+func (s *AlertService) UpdateAlertRule(ctx context.Context, req *pb.SaveAlertRuleRequest) (*pb.AlertRule, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	projectID, _, err := s.owners.Authorize(ctx, req.Rule.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(req.Rule.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule ID")
+	}
+
+	rule, err := toRule(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = ruleID
+	rule.ProjectID = projectID
+
+	err = s.repo.UpdateRule(ctx, rule)
+	if errors.Is(err, alert.ErrRuleNotFound) {
+		return nil, status.Error(codes.NotFound, "alert rule not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update alert rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return toAlertRule(rule), nil
+}
+
+func (s *AlertService) DeleteAlertRule(ctx context.Context, req *pb.DeleteAlertRuleRequest) (*pb.DeleteAlertRuleResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(req.RuleId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule ID")
+	}
+
+	err = s.repo.DeleteRule(ctx, projectID, ruleID)
+	if errors.Is(err, alert.ErrRuleNotFound) {
+		return nil, status.Error(codes.NotFound, "alert rule not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete alert rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return &pb.DeleteAlertRuleResponse{
+		Success: true,
+		Message: "Alert rule deleted successfully",
+	}, nil
+}
+
+func (s *AlertService) ListAlertRules(ctx context.Context, req *pb.ListAlertRulesRequest) (*pb.ListAlertRulesResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.ListRules(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list alert rules: %v", err)
+	}
+
+	resp := &pb.ListAlertRulesResponse{}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, toAlertRule(rule))
+	}
+	return resp, nil
+}
+
+func (s *AlertService) ListAlerts(ctx context.Context, req *pb.ListAlertsRequest) (*pb.ListAlertsResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.State != "" && req.State != alert.StateFiring && req.State != alert.StateResolved {
+		return nil, status.Error(codes.InvalidArgument, "state must be firing or resolved")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+	if limit > maxAlertsLimit {
+		limit = maxAlertsLimit
+	}
+
+	alerts, err := s.repo.ListAlerts(ctx, projectID, req.State, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list alerts: %v", err)
+	}
+
+	resp := &pb.ListAlertsResponse{}
+	for _, a := range alerts {
+		pbAlert := &pb.Alert{
+			Id:         a.ID.String(),
+			RuleId:     a.RuleID.String(),
+			RuleName:   a.RuleName,
+			ProjectId:  a.ProjectID.String(),
+			State:      a.State,
+			Value:      a.Value,
+			MatchCount: a.MatchCount,
+			Message:    a.Message,
+			FiredAt:    a.FiredAt.Format(time.RFC3339),
+			LastSeenAt: a.LastSeenAt.Format(time.RFC3339),
+		}
+		if a.ResolvedAt != nil {
+			pbAlert.ResolvedAt = a.ResolvedAt.Format(time.RFC3339)
+		}
+		resp.Alerts = append(resp.Alerts, pbAlert)
+	}
+	return resp, nil
+}
+
+// ListAnomalies returns the hours in which the log volume of the project
+// deviated from its baseline, newest first.
+func (s *AlertService) ListAnomalies(ctx context.Context, req *pb.ListAnomaliesRequest) (*pb.ListAnomaliesResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	if req.To != "" {
+		if to, err = time.Parse(time.RFC3339, req.To); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+		}
+	}
+	from := to.Add(-defaultAnomalyRange)
+	if req.From != "" {
+		if from, err = time.Parse(time.RFC3339, req.From); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+		}
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	if req.MinScore < 0 {
+		return nil, status.Error(codes.InvalidArgument, "min_score must not be negative")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+	if limit > maxAlertsLimit {
+		limit = maxAlertsLimit
+	}
+
+	anomalies, err := s.anomalyRepo.ListAnomalies(ctx, projectID, anomaly.Filter{
+		From:     from.UTC(),
+		To:       to.UTC(),
+		Category: req.Category,
+		MinScore: req.MinScore,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list anomalies: %v", err)
+	}
+
+	resp := &pb.ListAnomaliesResponse{}
+	for _, a := range anomalies {
+		resp.Anomalies = append(resp.Anomalies, &pb.Anomaly{
+			Id:         a.ID.String(),
+			ProjectId:  a.ProjectID.String(),
+			Category:   a.Category,
+			Hour:       a.Hour.Format(time.RFC3339),
+			Observed:   a.Observed,
+			Baseline:   a.Baseline,
+			Score:      a.Score,
+			Direction:  a.Direction,
+			DetectedAt: a.DetectedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+// reload refreshes the rules of this replica right away, other replicas pick
+// the change up on their next evaluation.
+func (s *AlertService) reload(ctx context.Context) {
+	if err := s.engine.Reload(ctx); err != nil {
+		// the rule is saved, the evaluation job retries the reload
+		slog.ErrorContext(ctx, "Error reloading alert rules", "err", err)
+	}
+}
+
+func toRule(r *pb.AlertRule) (*alert.Rule, error) {
+	if r.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if r.WindowSeconds <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "window_seconds must be positive")
+	}
+
+	switch r.Kind {
+	case alert.KindThreshold:
+		if r.Threshold < 0 {
+			return nil, status.Error(codes.InvalidArgument, "threshold must not be negative")
+		}
+	case alert.KindPattern:
+		if r.Pattern == "" {
+			return nil, status.Error(codes.InvalidArgument, "pattern is required for pattern rules")
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "kind must be threshold or pattern")
+	}
+
+	return &alert.Rule{
+		Name:      r.Name,
+		Kind:      r.Kind,
+		Category:  r.Category,
+		Pattern:   r.Pattern,
+		Threshold: r.Threshold,
+		Window:    time.Duration(r.WindowSeconds) * time.Second,
+		Enabled:   r.Enabled,
+	}, nil
+}
+
+func toAlertRule(rule *alert.Rule) *pb.AlertRule {
+	return &pb.AlertRule{
+		Id:            rule.ID.String(),
+		ProjectId:     rule.ProjectID.String(),
+		Name:          rule.Name,
+		Kind:          rule.Kind,
+		Category:      rule.Category,
+		Pattern:       rule.Pattern,
+		Threshold:     rule.Threshold,
+		WindowSeconds: int64(rule.Window.Seconds()),
+		Enabled:       rule.Enabled,
+		CreatedAt:     rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
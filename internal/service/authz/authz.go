@@ -0,0 +1,51 @@
+// Package authz checks that the users calling project scoped RPCs own the
+// project.
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/project"
+)
+
+// ProjectOwners authorizes requests against the owner of their project.
+type ProjectOwners struct {
+	repo project.Repository
+}
+
+func NewProjectOwners(repo project.Repository) *ProjectOwners {
+	return &ProjectOwners{repo: repo}
+}
+
+// Authorize checks that userID owns the project and returns the parsed
+// project ID together with the owner's account type. Errors are gRPC
+// statuses handlers return as they are.
+func (o *ProjectOwners) Authorize(ctx context.Context, rawProjectID, userID string) (uuid.UUID, string, error) {
+	if rawProjectID == "" || userID == "" {
+		return uuid.Nil, "", status.Error(codes.InvalidArgument, "project_id and user_id are required")
+	}
+
+	projectID, err := uuid.Parse(rawProjectID)
+	if err != nil {
+		return uuid.Nil, "", status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	ownerID, accountType, err := o.repo.GetOwner(ctx, projectID)
+	if errors.Is(err, project.ErrProjectNotFound) {
+		return uuid.Nil, "", status.Error(codes.NotFound, "project not found")
+	}
+	if err != nil {
+		return uuid.Nil, "", status.Errorf(codes.Internal, "failed to load project: %v", err)
+	}
+
+	if ownerID != userID {
+		return uuid.Nil, "", status.Error(codes.PermissionDenied, "user doesn't own this project")
+	}
+
+	return projectID, accountType, nil
+}
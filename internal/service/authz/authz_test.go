@@ -0,0 +1,68 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/project"
+)
+
+// owners answers GetOwner from a map, everything else is left unimplemented.
+type owners struct {
+	project.Repository
+	byProject map[uuid.UUID]string
+	err       error
+}
+
+func (o *owners) GetOwner(ctx context.Context, id uuid.UUID) (string, string, error) {
+	if o.err != nil {
+		return "", "", o.err
+	}
+	userID, ok := o.byProject[id]
+	if !ok {
+		return "", "", project.ErrProjectNotFound
+	}
+	return userID, "pro", nil
+}
+
+func TestAuthorize(t *testing.T) {
+	projectID := uuid.New()
+
+	tests := []struct {
+		name      string
+		projectID string
+		userID    string
+		repoErr   error
+		want      codes.Code
+	}{
+		{"owner", projectID.String(), "alice", nil, codes.OK},
+		{"missing project", "", "alice", nil, codes.InvalidArgument},
+		{"missing user", projectID.String(), "", nil, codes.InvalidArgument},
+		{"malformed project", "not-a-uuid", "alice", nil, codes.InvalidArgument},
+		{"unknown project", uuid.NewString(), "alice", nil, codes.NotFound},
+		{"other user", projectID.String(), "bob", nil, codes.PermissionDenied},
+		{"database error", projectID.String(), "alice", errors.New("connection refused"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewProjectOwners(&owners{byProject: map[uuid.UUID]string{projectID: "alice"}, err: tt.repoErr})
+
+			got, accountType, err := o.Authorize(context.Background(), tt.projectID, tt.userID)
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("code = %v, want %v (%v)", code, tt.want, err)
+			}
+			if tt.want != codes.OK {
+				return
+			}
+			if got != projectID || accountType != "pro" {
+				t.Errorf("got %s with account type %q, want %s and pro", got, accountType, projectID)
+			}
+		})
+	}
+}
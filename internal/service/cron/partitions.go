@@ -0,0 +1,288 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/jobrun"
+	"github.com/AjayShukla007/logsentinel/internal/repository/retention"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+const (
+	PartitionDaily  = "daily"
+	PartitionWeekly = "weekly"
+
+	partitionJobName = "partition_maintenance"
+
+	partitionTimeLayout = "2006-01-02 15:04:05"
+)
+
+var partitionBoundPattern = regexp.MustCompile(`FROM \('([^']+)'\) TO \('([^']+)'\)`)
+
+type logPartition struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// maintainPartitions creates the partitions of the coming days and drops
+// partitions whose logs have all expired. Partitions holding logs of a
+// project that still keeps them are left to the row based cleanup.
+func (s *CronService) maintainPartitions(ctx context.Context) error {
+	return s.runPartitionMaintenance(ctx, scheduler.RunFromContext(ctx))
+}
+
+func (s *CronService) runPartitionMaintenance(ctx context.Context, run *jobrun.Run) error {
+	partitions, err := s.listPartitions(ctx)
+	if err != nil {
+		return fmt.Errorf("listing partitions: %w", err)
+	}
+
+	now := time.Now()
+	start := s.partitionStart(now)
+	for i := 0; i <= s.config.PartitionsAhead; i++ {
+		end := s.partitionEnd(start)
+		if err := s.ensurePartition(ctx, partitions, start, end); err != nil {
+			return err
+		}
+		start = end
+	}
+
+	projects, err := s.retentionRepo.GetAllProjectRetention(ctx)
+	if err != nil {
+		return fmt.Errorf("loading retention policies: %w", err)
+	}
+	byProject := make(map[uuid.UUID]*retention.ProjectRetention, len(projects))
+	for _, project := range projects {
+		byProject[project.ProjectID] = project
+	}
+
+	for _, partition := range partitions {
+		droppable, err := s.partitionExpired(ctx, partition, byProject, now)
+		if err != nil {
+			return fmt.Errorf("checking partition %s: %w", partition.name, err)
+		}
+		if !droppable {
+			continue
+		}
+
+		rows, err := s.dropPartition(ctx, partition)
+		if err != nil {
+			return fmt.Errorf("dropping partition %s: %w", partition.name, err)
+		}
+		run.RowsAffected += rows
+	}
+
+	return nil
+}
+
+func (s *CronService) listPartitions(ctx context.Context) ([]logPartition, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'logs'::regclass
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []logPartition
+	for rows.Next() {
+		var name, bound string
+		if err := rows.Scan(&name, &bound); err != nil {
+			return nil, err
+		}
+
+		// the default partition has no range and is never dropped
+		match := partitionBoundPattern.FindStringSubmatch(bound)
+		if match == nil {
+			continue
+		}
+		start, err := time.Parse(partitionTimeLayout, match[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := time.Parse(partitionTimeLayout, match[2])
+		if err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, logPartition{name: name, start: start, end: end})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].start.Before(partitions[j].start)
+	})
+	return partitions, nil
+}
+
+// ensurePartition creates the partition for [start, end) unless an existing
+// partition already overlaps it. Rows that landed in the default partition
+// for that range are moved into the new partition before it is attached.
+func (s *CronService) ensurePartition(ctx context.Context, existing []logPartition, start, end time.Time) error {
+	for _, p := range existing {
+		if p.start.Before(end) && start.Before(p.end) {
+			return nil
+		}
+	}
+
+	name := s.partitionName(start)
+	table := pgx.Identifier{name}.Sanitize()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE %s (LIKE logs INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, table),
+		fmt.Sprintf(`
+			WITH moved AS (
+				DELETE FROM logs_default
+				WHERE created_at >= '%[2]s' AND created_at < '%[3]s'
+				RETURNING *
+			)
+			INSERT INTO %[1]s SELECT * FROM moved`,
+			table, start.Format(partitionTimeLayout), end.Format(partitionTimeLayout)),
+		fmt.Sprintf(`ALTER TABLE logs ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`,
+			table, start.Format(partitionTimeLayout), end.Format(partitionTimeLayout)),
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("creating partition %s: %w", name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("Created log partition", "partition", name)
+	return nil
+}
+
+// partitionExpired reports whether every log in the partition is past the
+// retention of its project. Projects keeping logs forever, archiving them or
+// unknown to the retention policies keep the whole partition alive.
+func (s *CronService) partitionExpired(ctx context.Context, partition logPartition, projects map[uuid.UUID]*retention.ProjectRetention, now time.Time) (bool, error) {
+	if !partition.end.Before(now) {
+		return false, nil
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(
+		`SELECT DISTINCT project_id FROM %s WHERE project_id IS NOT NULL`,
+		pgx.Identifier{partition.name}.Sanitize(),
+	))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID uuid.UUID
+		if err := rows.Scan(&projectID); err != nil {
+			return false, err
+		}
+
+		project, ok := projects[projectID]
+		if !ok {
+			return false, nil
+		}
+
+		// archived projects go through the row cleanup which archives
+		// before deleting
+		if s.shouldArchive(project.AccountType) {
+			return false, nil
+		}
+
+		days := maxRetentionDays(project)
+		if days == 0 || partition.end.After(cutoff(now, days)) {
+			return false, nil
+		}
+	}
+
+	return true, rows.Err()
+}
+
+// dropPartition detaches and drops a partition, it returns the planner's row
+// estimate since counting a whole partition would defeat the point.
+func (s *CronService) dropPartition(ctx context.Context, partition logPartition) (int64, error) {
+	table := pgx.Identifier{partition.name}.Sanitize()
+
+	var estimate float64
+	err := s.db.QueryRow(ctx, `SELECT reltuples FROM pg_class WHERE oid = $1::regclass`, partition.name).Scan(&estimate)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	if _, err := s.db.Exec(ctx, fmt.Sprintf(`ALTER TABLE logs DETACH PARTITION %s`, table)); err != nil {
+		return 0, err
+	}
+	if _, err := s.db.Exec(ctx, fmt.Sprintf(`DROP TABLE %s`, table)); err != nil {
+		return 0, err
+	}
+
+	slog.Info("Dropped expired log partition", "partition", partition.name)
+	if estimate < 0 {
+		return 0, nil
+	}
+	return int64(estimate), nil
+}
+
+func (s *CronService) partitionStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if s.config.PartitionInterval == PartitionWeekly {
+		// weeks start on monday
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func (s *CronService) partitionEnd(start time.Time) time.Time {
+	if s.config.PartitionInterval == PartitionWeekly {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func (s *CronService) partitionName(start time.Time) string {
+	if s.config.PartitionInterval == PartitionWeekly {
+		return "logs_w" + start.Format("20060102")
+	}
+	return "logs_p" + start.Format("20060102")
+}
+
+// maxRetentionDays is the longest retention across the categories of a
+// project, 0 when any of them keeps logs forever.
+func maxRetentionDays(project *retention.ProjectRetention) int {
+	if project.DefaultDays == 0 {
+		return 0
+	}
+
+	longest := project.DefaultDays
+	for _, days := range project.Categories {
+		if days == 0 {
+			return 0
+		}
+		if days > longest {
+			longest = days
+		}
+	}
+	return longest
+}
@@ -0,0 +1,225 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/repository/jobrun"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+)
+
+const retentionJobName = "retention_cleanup"
+
+// deleteOldLogs enforces the effective retention of every project in bounded
+// batches. Projects are visited in ID order, when a run is cut short by its
+// time budget or by shutdown the next run resumes at the project it stopped on.
+func (s *CronService) deleteOldLogs(ctx context.Context) error {
+	run := scheduler.RunFromContext(ctx)
+
+	resumeFrom := ""
+	if last, err := s.jobRunRepo.GetLastRun(ctx, retentionJobName); err != nil {
+		slog.Error("Error loading last retention run", "err", err)
+	} else if last != nil && last.Status != jobrun.StatusCompleted {
+		resumeFrom = last.ResumeCursor
+	}
+
+	err := s.runRetention(ctx, run, resumeFrom)
+	if err == nil {
+		run.ResumeCursor = ""
+	}
+	return err
+}
+
+func (s *CronService) runRetention(ctx context.Context, run *jobrun.Run, resumeFrom string) error {
+	projects, err := s.retentionRepo.GetAllProjectRetention(ctx)
+	if err != nil {
+		return fmt.Errorf("loading retention policies: %w", err)
+	}
+
+	deadline := time.Now().Add(s.config.MaxRunTime)
+	now := time.Now()
+
+	for _, project := range projects {
+		if resumeFrom != "" && project.ProjectID.String() < resumeFrom {
+			continue
+		}
+		run.ResumeCursor = project.ProjectID.String()
+
+		var targets []retentionTarget
+		categories := make([]string, 0, len(project.Categories))
+		for category, days := range project.Categories {
+			categories = append(categories, category)
+			if days > 0 {
+				targets = append(targets, retentionTarget{
+					condition: "category = $2",
+					category:  category,
+					cutoff:    cutoff(now, days),
+				})
+			}
+		}
+		if project.DefaultDays > 0 {
+			targets = append(targets, retentionTarget{
+				condition: "category <> ALL($2::text[])",
+				category:  categories,
+				cutoff:    cutoff(now, project.DefaultDays),
+			})
+		}
+
+		archive := s.shouldArchive(project.AccountType)
+		for _, target := range targets {
+			var err error
+			if archive {
+				err = s.archiveInBatches(ctx, run, deadline, project.ProjectID, target)
+			} else {
+				err = s.deleteInBatches(ctx, run, deadline, project.ProjectID, target)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// retentionTarget selects the expired logs of one retention rule of a
+// project. condition filters on category using $2, $1 is the project ID,
+// $3 the cutoff and $4 the batch size.
+type retentionTarget struct {
+	condition string
+	category  any
+	cutoff    time.Time
+}
+
+// deleteInBatches deletes expired logs until a batch comes back short.
+func (s *CronService) deleteInBatches(ctx context.Context, run *jobrun.Run, deadline time.Time, projectID uuid.UUID, target retentionTarget) error {
+	query := fmt.Sprintf(`
+		DELETE FROM logs
+		WHERE id IN (
+			SELECT id FROM logs
+			WHERE project_id = $1
+			AND %s
+			AND created_at < $3
+			LIMIT $4
+		)`, target.condition)
+
+	for {
+		if time.Now().After(deadline) {
+			return errBudgetExhausted()
+		}
+
+		result, err := s.db.Exec(ctx, query, projectID, target.category, target.cutoff, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+		run.RowsAffected += result.RowsAffected()
+
+		if result.RowsAffected() < int64(s.config.BatchSize) {
+			return nil
+		}
+
+		if err := s.pause(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveInBatches copies each batch of expired logs to the archive before
+// deleting exactly the rows that were archived.
+func (s *CronService) archiveInBatches(ctx context.Context, run *jobrun.Run, deadline time.Time, projectID uuid.UUID, target retentionTarget) error {
+	query := fmt.Sprintf(`
+		SELECT id, category, message, created_at, metadata, tags, COALESCE(trace_id, ''), COALESCE(pattern_id, ''), logged_at
+		FROM logs
+		WHERE project_id = $1
+		AND %s
+		AND created_at < $3
+		ORDER BY created_at
+		LIMIT $4`, target.condition)
+
+	for {
+		if time.Now().After(deadline) {
+			return errBudgetExhausted()
+		}
+
+		rows, err := s.db.Query(ctx, query, projectID, target.category, target.cutoff, s.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		var records []archive.Record
+		var ids []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			record := archive.Record{ProjectID: projectID.String()}
+			if err := rows.Scan(&id, &record.Category, &record.Message, &record.CreatedAt, &record.Metadata, &record.Tags, &record.TraceID, &record.PatternID, &record.LoggedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			record.ID = id.String()
+			records = append(records, record)
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			return nil
+		}
+
+		if err := s.archiver.Archive(ctx, projectID, records); err != nil {
+			return fmt.Errorf("archiving logs of project %s: %w", projectID, err)
+		}
+
+		result, err := s.db.Exec(ctx, `DELETE FROM logs WHERE project_id = $1 AND id = ANY($2)`, projectID, ids)
+		if err != nil {
+			return err
+		}
+		run.RowsAffected += result.RowsAffected()
+
+		if len(records) < s.config.BatchSize {
+			return nil
+		}
+
+		if err := s.pause(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// shouldArchive reports whether expired logs of an account type are archived
+// before they are deleted.
+func (s *CronService) shouldArchive(accountType string) bool {
+	if s.archiver == nil {
+		return false
+	}
+	for _, plan := range s.config.ArchivePlans {
+		if plan == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CronService) pause(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.config.BatchPause):
+		return nil
+	}
+}
+
+func errBudgetExhausted() error {
+	return fmt.Errorf("retention run time budget exhausted: %w", scheduler.ErrIncomplete)
+}
+
+func cutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
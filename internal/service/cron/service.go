@@ -1,56 +1,103 @@
 package cron
 
 import (
-	"context"
-	"log"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/repository/jobrun"
+	"github.com/AjayShukla007/logsentinel/internal/repository/retention"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
 )
 
-type CronService struct {
-	db *pgxpool.Pool
+// Config controls when the maintenance jobs run and how the retention
+// cleanup spreads its work.
+type Config struct {
+	// RetentionSchedule is the cron expression of the retention cleanup
+	RetentionSchedule string
+	// PartitionSchedule is the cron expression of partition maintenance
+	PartitionSchedule string
+	// BatchSize is the maximum number of rows removed by one DELETE
+	BatchSize int
+	// BatchPause is the sleep between batches so autovacuum and regular
+	// traffic can keep up
+	BatchPause time.Duration
+	// MaxRunTime is the time budget of one run, remaining work is resumed
+	// on the next run
+	MaxRunTime time.Duration
+	// PartitionInterval is the range covered by one logs partition, either
+	// PartitionDaily or PartitionWeekly
+	PartitionInterval string
+	// PartitionsAhead is how many future partitions are kept created
+	PartitionsAhead int
+	// ArchivePlans lists the account types whose expired logs are archived
+	// before deletion, it only applies when an archiver is configured
+	ArchivePlans []string
 }
 
-func NewCronService(db *pgxpool.Pool) *CronService {
-	return &CronService{
-		db: db,
+func DefaultConfig() Config {
+	return Config{
+		RetentionSchedule: "0 3 * * *",
+		PartitionSchedule: "30 * * * *",
+		BatchSize:         5000,
+		BatchPause:        200 * time.Millisecond,
+		MaxRunTime:        30 * time.Minute,
+
+		PartitionInterval: PartitionDaily,
+		PartitionsAhead:   7,
+
+		ArchivePlans: []string{"pro"},
 	}
 }
 
-func (s *CronService) Start() {
-	go s.scheduleCleanup()
+type CronService struct {
+	db            *pgxpool.Pool
+	retentionRepo retention.Repository
+	jobRunRepo    jobrun.Repository
+	archiver      *archive.Archiver
+	config        Config
 }
 
-func (s *CronService) scheduleCleanup() {
-	// For testing, using a shorter interval
-	// ticker := time.NewTicker(1 * time.Minute) // Run every minute instead of every 24 hours
-	//TODO: change the interval value to 24 hours
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
-
-	for {
-		s.deleteOldLogs()
-		<-ticker.C
+// NewCronService creates the maintenance jobs, archiver may be nil to delete
+// expired logs without archiving them.
+func NewCronService(db *pgxpool.Pool, retentionRepo retention.Repository, jobRunRepo jobrun.Repository, archiver *archive.Archiver, config Config) *CronService {
+	return &CronService{
+		db:            db,
+		retentionRepo: retentionRepo,
+		jobRunRepo:    jobRunRepo,
+		archiver:      archiver,
+		config:        config,
 	}
 }
 
-func (s *CronService) deleteOldLogs() {
-	query := `
-        DELETE FROM logs l
-        USING projects p, users u
-        WHERE l.project_id = p.id 
-        AND p.user_id = u.id 
-        AND u.account_type = 'free'
-        AND l.created_at < NOW() - INTERVAL '7 days'
-    `
-
-	result, err := s.db.Exec(context.Background(), query)
-	if err != nil {
-		log.Printf("Error deleting old logs: %v", err)
-		return
+// RegisterJobs adds the maintenance jobs to the scheduler. Partition
+// maintenance also runs at startup so the current partitions always exist.
+func (s *CronService) RegisterJobs(sched *scheduler.Scheduler) error {
+	jobs := []scheduler.Job{
+		{
+			Name:       partitionJobName,
+			Schedule:   s.config.PartitionSchedule,
+			Timeout:    10 * time.Minute,
+			Jitter:     time.Minute,
+			RunOnStart: true,
+			Run:        s.maintainPartitions,
+		},
+		{
+			Name:     retentionJobName,
+			Schedule: s.config.RetentionSchedule,
+			// the cleanup stops itself at MaxRunTime, the timeout only
+			// guards against a statement hanging past it
+			Timeout: s.config.MaxRunTime + 5*time.Minute,
+			Jitter:  5 * time.Minute,
+			Run:     s.deleteOldLogs,
+		},
 	}
 
-	rowsAffected := result.RowsAffected()
-	log.Printf("Deleted %d old logs from free tier users", rowsAffected)
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
 }
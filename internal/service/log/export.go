@@ -0,0 +1,214 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/export"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+const (
+	// exportFetchSize is the number of rows fetched from the export cursor
+	// at a time, every fetch ends with a chunk on a row boundary
+	exportFetchSize = 1000
+	// exportMaxChunk caps the payload of a single ExportChunk
+	exportMaxChunk = 1 << 20
+)
+
+// ExportLogs streams all logs of a project in a time range, including logs
+// of ready rehydrations. Rows are read through a server side cursor so memory
+// stays flat regardless of the range.
+// An interrupted export can resume either after the last_id of the last
+// chunk received, producing a new file, or at a byte offset of an identical
+// export, which only makes sense while the range receives no new logs.
+func (s *LogService) ExportLogs(req *pb.ExportLogsRequest, stream pb.LogService_ExportLogsServer) error {
+	ctx := stream.Context()
+
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	if _, err := s.authenticate(ctx, req.ProjectId, req.ApiKey, req.ClientId); err != nil {
+		return err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	if req.ResumeOffset < 0 {
+		return status.Error(codes.InvalidArgument, "resume_offset must not be negative")
+	}
+
+	sender := &chunkSender{stream: stream, skip: req.ResumeOffset}
+	encoder, err := export.NewEncoder(req.Format, req.Compression, &sender.buf)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly, IsoLevel: pgx.RepeatableRead})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to start export: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	query := `
+		DECLARE export_cursor NO SCROLL CURSOR FOR
+		SELECT id, category, message, created_at, metadata, tags, COALESCE(trace_id, ''), COALESCE(pattern_id, ''), logged_at
+		FROM searchable_logs
+		WHERE project_id = $1 AND created_at >= $2 AND created_at < $3`
+	args := []any{projectID, from, to}
+
+	if req.ResumeAfterId != "" {
+		afterID, err := uuid.Parse(req.ResumeAfterId)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid resume_after_id")
+		}
+
+		var afterCreatedAt time.Time
+		err = tx.QueryRow(ctx, `SELECT created_at FROM searchable_logs WHERE project_id = $1 AND id = $2 LIMIT 1`, projectID, afterID).Scan(&afterCreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.NotFound, "resume_after_id not found")
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to resume export: %v", err)
+		}
+
+		query += ` AND (created_at, id) > ($4, $5)`
+		args = append(args, afterCreatedAt, afterID)
+	}
+	query += ` ORDER BY created_at, id`
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return status.Errorf(codes.Internal, "failed to open export cursor: %v", err)
+	}
+
+	fetch := fmt.Sprintf(`FETCH %d FROM export_cursor`, exportFetchSize)
+	for {
+		select {
+		case <-s.draining:
+			return status.Errorf(codes.Unavailable, "server is shutting down, resume after %s", sender.lastID)
+		default:
+		}
+
+		records, err := fetchExportRows(ctx, tx, fetch, req.ProjectId)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read logs: %v", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		if err := encoder.Write(records); err != nil {
+			return status.Errorf(codes.Internal, "failed to encode logs: %v", err)
+		}
+		if err := encoder.Flush(); err != nil {
+			return status.Errorf(codes.Internal, "failed to encode logs: %v", err)
+		}
+
+		sender.rows += int64(len(records))
+		sender.lastID = records[len(records)-1].ID
+		if err := sender.send(false); err != nil {
+			return err
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return status.Errorf(codes.Internal, "failed to finish export: %v", err)
+	}
+	return sender.send(true)
+}
+
+func fetchExportRows(ctx context.Context, tx pgx.Tx, fetch, projectID string) ([]archive.Record, error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []archive.Record
+	for rows.Next() {
+		var id uuid.UUID
+		record := archive.Record{ProjectID: projectID}
+		if err := rows.Scan(&id, &record.Category, &record.Message, &record.CreatedAt, &record.Metadata, &record.Tags, &record.TraceID, &record.PatternID, &record.LoggedAt); err != nil {
+			return nil, err
+		}
+		record.ID = id.String()
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// chunkSender turns the encoded output into ExportChunks. The first skip
+// bytes are dropped so a client can resume from a byte offset.
+type chunkSender struct {
+	stream pb.LogService_ExportLogsServer
+	buf    bytes.Buffer
+	offset int64
+	skip   int64
+	rows   int64
+	lastID string
+}
+
+func (c *chunkSender) send(done bool) error {
+	data := c.buf.Bytes()
+	c.buf.Reset()
+
+	if c.offset < c.skip {
+		drop := c.skip - c.offset
+		if drop > int64(len(data)) {
+			drop = int64(len(data))
+		}
+		data = data[drop:]
+		c.offset += drop
+	}
+
+	for {
+		n := len(data)
+		if n > exportMaxChunk {
+			n = exportMaxChunk
+		}
+		last := n == len(data)
+
+		// empty chunks are only worth sending to announce the end
+		if n > 0 || (last && done) {
+			chunk := &pb.ExportChunk{
+				Data:   data[:n],
+				Offset: c.offset,
+				Rows:   c.rows,
+				Done:   last && done,
+			}
+			if last {
+				chunk.LastId = c.lastID
+			}
+			if err := c.stream.Send(chunk); err != nil {
+				return err
+			}
+		}
+
+		c.offset += int64(n)
+		data = data[n:]
+		if last {
+			return nil
+		}
+	}
+}
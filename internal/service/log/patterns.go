@@ -0,0 +1,78 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+const (
+	defaultPatternLimit   = 20
+	maxPatternLimit       = 200
+	defaultPatternSamples = 3
+	maxPatternSamples     = 20
+)
+
+// GetTopPatterns returns the message patterns of a project with the most
+// logs in a time range. Counts come from the pattern counts, so the newest
+// logs may lag ingestion by one flush.
+func (s *LogService) GetTopPatterns(ctx context.Context, req *pb.GetTopPatternsRequest) (*pb.TopPatterns, error) {
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	if _, err := s.authenticate(ctx, req.ProjectId, req.ApiKey, req.ClientId); err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	limit := int(req.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultPatternLimit
+	case limit < 0 || limit > maxPatternLimit:
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxPatternLimit)
+	}
+	samples := int(req.Samples)
+	switch {
+	case samples == 0:
+		samples = defaultPatternSamples
+	case samples < 0 || samples > maxPatternSamples:
+		return nil, status.Errorf(codes.InvalidArgument, "samples must be between 1 and %d", maxPatternSamples)
+	}
+
+	top, err := s.patternRepo.TopPatterns(ctx, projectID, from.UTC(), to.UTC(), limit, samples)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load patterns: %v", err)
+	}
+
+	resp := &pb.TopPatterns{ProjectId: req.ProjectId}
+	for _, t := range top {
+		resp.Patterns = append(resp.Patterns, &pb.LogPattern{
+			Id:        t.ID,
+			Template:  t.Template,
+			Count:     t.Logs,
+			FirstSeen: t.FirstSeen.Format(time.RFC3339),
+			LastSeen:  t.LastSeen.Format(time.RFC3339),
+			Samples:   t.Samples,
+		})
+	}
+	return resp, nil
+}
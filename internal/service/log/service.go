@@ -2,28 +2,65 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/AjayShukla007/logsentinel/internal/alerting"
+	"github.com/AjayShukla007/logsentinel/internal/category"
+	"github.com/AjayShukla007/logsentinel/internal/certs"
+	"github.com/AjayShukla007/logsentinel/internal/clustering"
+	"github.com/AjayShukla007/logsentinel/internal/metrics"
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/pipeline"
 	"github.com/AjayShukla007/logsentinel/internal/ratelimit"
+	"github.com/AjayShukla007/logsentinel/internal/redact"
+	"github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	"github.com/AjayShukla007/logsentinel/internal/repository/pattern"
+	"github.com/AjayShukla007/logsentinel/internal/rollup"
 	// "github.com/AjayShukla007/logsentinel/internal/repository/project"
 	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+var tracer = otel.Tracer("github.com/AjayShukla007/logsentinel/internal/service/log")
+
 type LogService struct {
 	pb.UnimplementedLogServiceServer
 	db          *pgxpool.Pool
 	rateLimiter *ratelimit.RateLimiter
+	alerts      *alerting.Engine
+	rollups     *rollup.Aggregator
+	statsRepo   logstats.Repository
+	patterns    *clustering.Clusterer
+	patternRepo pattern.Repository
+	redactor    *redact.Redactor
+	processor   *pipeline.Engine
+	categories  *category.Registry
+
+	// draining is closed once shutdown starts; long lived streams watch it
+	// and new ingestion is rejected while inflight tracks pending writes
+	draining   chan struct{}
+	drainMu    sync.RWMutex
+	isDraining bool
+	inflight   sync.WaitGroup
 }
 
+// drainReconnectDelay is the backoff suggested to streaming clients when the
+// server drains their connection
+const drainReconnectDelay = 2 * time.Second
+
 type ConnectionManager struct {
 	connections map[string]*ConnectionInfo
 	mu          sync.RWMutex
@@ -37,49 +74,159 @@ type ConnectionInfo struct {
 	isProAccount bool
 }
 
-func NewLogService(db *pgxpool.Pool) *LogService {
+func NewLogService(db *pgxpool.Pool, rateLimiter *ratelimit.RateLimiter, alerts *alerting.Engine, notifier *notify.Dispatcher, rollups *rollup.Aggregator, statsRepo logstats.Repository, patterns *clustering.Clusterer, patternRepo pattern.Repository, redactor *redact.Redactor, processor *pipeline.Engine, categories *category.Registry) *LogService {
+	rateLimiter.OnReject(func(clientID string, limit int) {
+		notifier.Notify(notify.Event{
+			Kind:     notify.KindRateLimited,
+			Severity: notify.SeverityWarning,
+			Title:    "Client exceeded its log rate limit",
+			ClientID: clientID,
+			Fields: map[string]string{
+				"limit_per_minute": fmt.Sprint(limit),
+			},
+		})
+	})
+
 	return &LogService{
 		db:          db,
-		rateLimiter: ratelimit.NewRateLimiter(),
+		rateLimiter: rateLimiter,
+		alerts:      alerts,
+		rollups:     rollups,
+		statsRepo:   statsRepo,
+		patterns:    patterns,
+		patternRepo: patternRepo,
+		redactor:    redactor,
+		processor:   processor,
+		categories:  categories,
+		draining:    make(chan struct{}),
 	}
 }
 
-func (s *LogService) Test(req *pb.TestRequest, stream pb.LogService_TestServer) error {
-	for {
-		timestamp := time.Now().Format("2006-01-02 15:04:05")
-		response := &pb.TestResponse{
-			Message:   "active",
-			Timestamp: timestamp,
-		}
+// Drain stops the service from accepting new logs and signals every open
+// stream to wrap up. Streaming clients receive a DrainNotice before their
+// stream is closed so they can reconnect to another replica.
+func (s *LogService) Drain() {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
 
-		if err := stream.Send(response); err != nil {
-			return fmt.Errorf("error sending test response: %v", err)
+	if s.isDraining {
+		return
+	}
+	s.isDraining = true
+	close(s.draining)
+}
+
+// Wait blocks until all in-flight log writes have finished or ctx expires.
+func (s *LogService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// beginWrite registers an in-flight write, it returns false once the service
+// is draining so callers can reject the log instead.
+func (s *LogService) beginWrite() bool {
+	s.drainMu.RLock()
+	defer s.drainMu.RUnlock()
+
+	if s.isDraining {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+func (s *LogService) endWrite() {
+	s.inflight.Done()
+}
+
+// authenticate checks the credentials of a project's client and returns the
+// owner's account type. Without an API key the client certificate of the
+// connection is checked instead.
+func (s *LogService) authenticate(ctx context.Context, projectID, apiKey, clientID string) (string, error) {
+	if apiKey == "" {
+		if accountType, _, ok := s.authenticateCert(ctx, projectID); ok {
+			return accountType, nil
 		}
+		return "", status.Error(codes.Unauthenticated, "invalid credentials")
+	}
 
-		time.Sleep(2 * time.Second)
+	var accountType string
+	err := s.db.QueryRow(ctx, `
+		SELECT u.account_type
+		FROM users u
+		JOIN projects p ON p.user_id = u.id
+		WHERE p.id = $1 AND p.api_key = $2 AND u.client_id = $3`,
+		projectID, apiKey, clientID,
+	).Scan(&accountType)
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid credentials")
 	}
+	return accountType, nil
 }
 
-func (s *LogService) SendLog(ctx context.Context, req *pb.LogRequest) (*pb.LogResponse, error) {
-	// TODO: remove these in production server as this might leak important data
-    fmt.Printf("Received log request: projectId=%s clientId=%s\n", req.ProjectId, req.ClientId)
-    var accountType string
+// authenticateCert authenticates a client by the verified certificate it
+// presented over mutual TLS, when one of its identities is mapped to the
+// project. It returns the owner's account type and client ID.
+func (s *LogService) authenticateCert(ctx context.Context, rawProjectID string) (string, string, bool) {
+	identities := certs.Identities(ctx)
+	if len(identities) == 0 {
+		return "", "", false
+	}
+	projectID, err := uuid.Parse(rawProjectID)
+	if err != nil {
+		return "", "", false
+	}
+	ctx, span := tracer.Start(ctx, "LogService.authenticateCert")
+	defer span.End()
 
+	var accountType, clientID string
+	err = s.db.QueryRow(ctx, `
+		SELECT u.account_type, u.client_id
+		FROM project_client_identities i
+		JOIN projects p ON p.id = i.project_id
+		JOIN users u ON u.id = p.user_id
+		WHERE p.id = $1 AND i.identity = ANY($2)
+		LIMIT 1`,
+		projectID, identities,
+	).Scan(&accountType, &clientID)
+	if err != nil {
+		return "", "", false
+	}
+	return accountType, clientID, true
+}
+
+// checkApiKey checks the project, API key and client ID of a log and
+// returns the owner's account type, or the response rejecting the log.
+func (s *LogService) checkApiKey(ctx context.Context, req *pb.LogRequest) (string, *pb.LogResponse) {
+	ctx, span := tracer.Start(ctx, "LogService.checkApiKey")
+	defer span.End()
+
+	var accountType string
 	var projectExists bool
 	err := s.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1)`, req.ProjectId).Scan(&projectExists)
 	if err != nil {
-		fmt.Printf("Error checking project existence: %v\n", err)
-		return &pb.LogResponse{
+		slog.ErrorContext(ctx, "Error checking project existence", "project_id", req.ProjectId, "err", err)
+		return "", &pb.LogResponse{
 			Success: false,
 			Message: "Database error when checking project",
-		}, nil
+		}
 	}
 
 	if !projectExists {
-		return &pb.LogResponse{
+		return "", &pb.LogResponse{
 			Success: false,
 			Message: "Project not found",
-		}, nil
+		}
 	}
 
 	var apiKeyMatches bool
@@ -88,18 +235,18 @@ func (s *LogService) SendLog(ctx context.Context, req *pb.LogRequest) (*pb.LogRe
 	`, req.ProjectId, req.ApiKey).Scan(&apiKeyMatches)
 
 	if err != nil {
-		fmt.Printf("Error checking API key: %v\n", err)
-		return &pb.LogResponse{
+		slog.ErrorContext(ctx, "Error checking API key", "project_id", req.ProjectId, "err", err)
+		return "", &pb.LogResponse{
 			Success: false,
 			Message: "Database error when checking API key",
-		}, nil
+		}
 	}
 
 	if !apiKeyMatches {
-		return &pb.LogResponse{
+		return "", &pb.LogResponse{
 			Success: false,
 			Message: "Invalid API key",
-		}, nil
+		}
 	}
 
 	err = s.db.QueryRow(ctx, `
@@ -110,29 +257,162 @@ func (s *LogService) SendLog(ctx context.Context, req *pb.LogRequest) (*pb.LogRe
 	`, req.ProjectId, req.ClientId).Scan(&accountType)
 
 	if err != nil {
-		fmt.Printf("Error checking user relationship: %v\n", err)
-		return &pb.LogResponse{
+		slog.DebugContext(ctx, "Client ID does not own the project", "project_id", req.ProjectId, "err", err)
+		return "", &pb.LogResponse{
 			Success: false,
 			Message: "Invalid client ID or user doesn't own this project",
-		}, nil
+		}
+	}
+
+	return accountType, nil
+}
+
+// errLogDropped is returned by saveLog for logs a rule dropped, wrapped in
+// errLogFiltered or errLogRedacted depending on the kind of rule
+var (
+	errLogDropped  = errors.New("log dropped")
+	errLogFiltered = fmt.Errorf("%w by processing rule", errLogDropped)
+	errLogRedacted = fmt.Errorf("%w by redaction rule", errLogDropped)
+)
+
+// saveLog parses a log and runs it through the processing rules and
+// redaction, stores it with its pattern and hands it to the alert engine.
+func (s *LogService) saveLog(ctx context.Context, req *pb.LogRequest) error {
+	ctx, span := tracer.Start(ctx, "LogService.saveLog", trace.WithAttributes(attribute.String("logsentinel.project_id", req.ProjectId)))
+	defer span.End()
+
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	processed := s.processor.Process(projectID, pipeline.Log{
+		Category: req.Category,
+		Message:  req.Message,
+		Metadata: req.Metadata,
+	})
+	if processed.Dropped {
+		metrics.LogsDropped.WithLabelValues(projectID.String(), "processing").Inc()
+		return fmt.Errorf("%w %s", errLogFiltered, processed.DroppedBy)
+	}
+	// rules may be older than a change to the categories of the project
+	logCategory, err := s.categories.Resolve(projectID, processed.Log.Category)
+	if err != nil {
+		return err
+	}
+	tags := processed.Log.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var loggedAt *time.Time
+	if !processed.Log.Time.IsZero() {
+		loggedAt = &processed.Log.Time
+	}
+
+	redacted := s.redactor.Apply(projectID, processed.Log.Message, processed.Log.Metadata)
+	if redacted.Dropped {
+		metrics.LogsDropped.WithLabelValues(projectID.String(), "redaction").Inc()
+		return fmt.Errorf("%w %s", errLogRedacted, redacted.DroppedBy)
+	}
+	message, metadata := redacted.Message, redacted.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+
+	patternID := s.patterns.Match(ctx, projectID, message)
+
+	var createdAt time.Time
+	err = s.db.QueryRow(ctx, `
+		INSERT INTO logs (project_id, category, message, metadata, pattern_id, tags, trace_id, logged_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), $8)
+		RETURNING created_at`,
+		projectID, logCategory, message, metadata, patternID, tags, processed.Log.TraceID, loggedAt,
+	).Scan(&createdAt)
+	if err != nil {
+		return err
+	}
+
+	metrics.LogsIngested.WithLabelValues(projectID.String(), logCategory).Inc()
+	s.rollups.Record(projectID, logCategory, len(message), createdAt)
+	s.patterns.Record(projectID, patternID, createdAt)
+	s.alerts.Observe(alerting.Event{
+		ProjectID: projectID,
+		Category:  logCategory,
+		Message:   message,
+		CreatedAt: createdAt,
+	})
+	return nil
+}
+
+func (s *LogService) Test(req *pb.TestRequest, stream pb.LogService_TestServer) error {
+	for {
+		timestamp := time.Now().Format("2006-01-02 15:04:05")
+		response := &pb.TestResponse{
+			Message:   "active",
+			Timestamp: timestamp,
+		}
+
+		if err := stream.Send(response); err != nil {
+			return fmt.Errorf("error sending test response: %v", err)
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.draining:
+			return nil
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (s *LogService) SendLog(ctx context.Context, req *pb.LogRequest) (*pb.LogResponse, error) {
+	slog.DebugContext(ctx, "Received log request", "project_id", req.ProjectId)
+	// clients authenticated by their certificate may leave the API key and
+	// client ID empty, the project owner's client ID is used then
+	accountType, clientID, certAuth := "", "", false
+	if req.ApiKey == "" {
+		accountType, clientID, certAuth = s.authenticateCert(ctx, req.ProjectId)
+	}
+	if !certAuth {
+		var failure *pb.LogResponse
+		if accountType, failure = s.checkApiKey(ctx, req); failure != nil {
+			return failure, nil
+		}
+		clientID = req.ClientId
 	}
 
 	isProAccount := accountType == "pro"
-	if !s.rateLimiter.AllowLog(req.ClientId, isProAccount) {
+	if !s.rateLimiter.AllowLog(clientID, isProAccount) {
 		return &pb.LogResponse{
 			Success: false,
 			Message: "Rate limit exceeded",
 		}, nil
 	}
 
-	_, err = s.db.Exec(ctx, `
-		INSERT INTO logs (project_id, category, message)
-		VALUES ($1, $2, $3)`,
-		req.ProjectId, req.Category, req.Message,
-	)
+	if !s.beginWrite() {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	defer s.endWrite()
 
+	err := s.saveLog(ctx, req)
+	if errors.Is(err, category.ErrInvalidCategory) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, errLogFiltered) {
+		return &pb.LogResponse{
+			Success: false,
+			Message: "Log dropped by processing rule",
+		}, nil
+	}
+	if errors.Is(err, errLogRedacted) {
+		return &pb.LogResponse{
+			Success: false,
+			Message: "Log dropped by redaction rule",
+		}, nil
+	}
 	if err != nil {
-		fmt.Printf("Error inserting log: %v\n", err)
+		slog.ErrorContext(ctx, "Error inserting log", "project_id", req.ProjectId, "err", err)
 		return &pb.LogResponse{
 			Success: false,
 			Message: "Failed to save log",
@@ -146,7 +426,7 @@ func (s *LogService) SendLog(ctx context.Context, req *pb.LogRequest) (*pb.LogRe
 }
 
 func (s *LogService) StreamLogs(req *pb.LogRequest, stream pb.LogService_StreamLogsServer) error {
-    fmt.Printf("Received stream logs request: projectId=%s\n", req.ProjectId)
+	slog.DebugContext(stream.Context(), "Received stream logs request", "project_id", req.ProjectId)
 	/* // Database query pseudocode for production
 	   rows, err := s.db.Query(context.Background(), `
 	       SELECT id, level, message, created_at
@@ -162,7 +442,7 @@ func (s *LogService) StreamLogs(req *pb.LogRequest, stream pb.LogService_StreamL
 		projectHint = "unknown-project"
 	}
 
-	// this is an fixed reference time that won't change between requests 
+	// this is an fixed reference time that won't change between requests
 	// in production we would store this per project or user session
 	referenceTime := time.Now()
 
@@ -197,7 +477,7 @@ func (s *LogService) StreamLogs(req *pb.LogRequest, stream pb.LogService_StreamL
 	loadMore := false
 	if req.Message == "LOAD_MORE" {
 		loadMore = true
-		fmt.Printf("Load more request received for project: %s\n", projectHint)
+		slog.DebugContext(stream.Context(), "Load more request received", "project_id", projectHint)
 	}
 
 	startIndex := 0
@@ -229,6 +509,9 @@ func (s *LogService) StreamLogs(req *pb.LogRequest, stream pb.LogService_StreamL
 		return nil
 	}
 
+	metrics.StreamSubscribers.Inc()
+	defer metrics.StreamSubscribers.Dec()
+
 	infoTicker := time.NewTicker(2 * time.Second)
 	errorTicker := time.NewTicker(10 * time.Second)
 	defer infoTicker.Stop()
@@ -239,6 +522,9 @@ func (s *LogService) StreamLogs(req *pb.LogRequest, stream pb.LogService_StreamL
 		case <-stream.Context().Done():
 			return nil
 
+		case <-s.draining:
+			return nil
+
 		case t := <-infoTicker.C:
 			formattedMessage := fmt.Sprintf("[%s] [info] [Project: %s] Regular system update",
 				t.Format("2006-01-02 15:04:05"),
@@ -271,34 +557,50 @@ func (s *LogService) BatchSendLogs(stream pb.LogService_BatchSendLogsServer) err
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to receive initial log: %v", err)
 	}
-	var count int32
-	var accountType string
-	err = s.db.QueryRow(context.Background(), `
-		SELECT u.account_type 
-		FROM users u 
-		JOIN projects p ON p.user_id = u.id 
-		WHERE p.id = $1 AND p.api_key = $2 AND u.client_id = $3`,
-		firstLog.ProjectId, firstLog.ApiKey, firstLog.ClientId,
-	).Scan(&accountType)
+	accountType, clientID, certAuth := "", "", false
+	if firstLog.ApiKey == "" {
+		accountType, clientID, certAuth = s.authenticateCert(stream.Context(), firstLog.ProjectId)
+	}
+	if !certAuth {
+		err = s.db.QueryRow(stream.Context(), `
+			SELECT u.account_type 
+			FROM users u 
+			JOIN projects p ON p.user_id = u.id 
+			WHERE p.id = $1 AND p.api_key = $2 AND u.client_id = $3`,
+			firstLog.ProjectId, firstLog.ApiKey, firstLog.ClientId,
+		).Scan(&accountType)
 
-	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "invalid credentials: %v", err)
+		}
+		clientID = firstLog.ClientId
 	}
 
 	isProAccount := accountType == "pro"
-	if !s.rateLimiter.AllowLog(firstLog.ClientId, isProAccount) {
+	if !s.rateLimiter.AllowLog(clientID, isProAccount) {
 		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
 	}
 
-	_, err = s.db.Exec(context.Background(), `
-		INSERT INTO logs (project_id, category, message)
-		VALUES ($1, $2, $3)`,
-		firstLog.ProjectId, firstLog.Category, firstLog.Message,
-	)
+	return s.saveBatch(stream, firstLog, clientID, isProAccount, s.saveLog)
+}
 
-	if err != nil {
+// saveBatch saves the first log of an authenticated batch and the rest of the
+// stream with save. The response counts every log saved or dropped so far.
+func (s *LogService) saveBatch(stream pb.LogService_BatchSendLogsServer, firstLog *pb.LogRequest, clientID string, isProAccount bool, save func(context.Context, *pb.LogRequest) error) error {
+	if !s.beginWrite() {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	err := save(context.WithoutCancel(stream.Context()), firstLog)
+	s.endWrite()
+
+	if errors.Is(err, category.ErrInvalidCategory) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	// dropped logs count as processed, resending them would drop them again
+	if err != nil && !errors.Is(err, errLogDropped) {
 		return status.Errorf(codes.Internal, "failed to save log: %v", err)
 	}
+	count := int32(1)
 
 	for {
 		logReq, err := stream.Recv()
@@ -317,17 +619,26 @@ func (s *LogService) BatchSendLogs(stream pb.LogService_BatchSendLogsServer) err
 			return status.Errorf(codes.InvalidArgument, "credentials must be consistent in batch")
 		}
 
-		if !s.rateLimiter.AllowLog(logReq.ClientId, isProAccount) {
+		if !s.rateLimiter.AllowLog(clientID, isProAccount) {
 			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
 		}
 
-		_, err = s.db.Exec(context.Background(), `
-			INSERT INTO logs (project_id, category, message)
-			VALUES ($1, $2, $3)`,
-			logReq.ProjectId, logReq.Category, logReq.Message,
-		)
+		// Logs received so far are persisted, the client resends the rest
+		// to another replica using the count it got back
+		if !s.beginWrite() {
+			return stream.SendAndClose(&pb.BatchLogResponse{
+				Success: false,
+				Message: "Server is shutting down, resend remaining logs",
+				Count:   count,
+			})
+		}
+		err = save(context.WithoutCancel(stream.Context()), logReq)
+		s.endWrite()
 
-		if err != nil {
+		if errors.Is(err, category.ErrInvalidCategory) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err != nil && !errors.Is(err, errLogDropped) {
 			return status.Errorf(codes.Internal, "failed to save log: %v", err)
 		}
 
@@ -403,36 +714,64 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 	done := make(chan struct{})
 	defer close(done)
 
+	// Receiving happens on its own goroutine so the loop below can also react
+	// to heartbeats and server shutdown. All sends stay on this goroutine
+	// since grpc streams don't allow concurrent Send calls.
+	messages := make(chan *pb.ClientMessage)
+	recvErr := make(chan error, 1)
 	go func() {
 		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
 			select {
+			case messages <- msg:
 			case <-done:
 				return
-			case <-heartbeatTicker.C:
-				if isAuthenticated {
-					if err := stream.Send(&pb.ServerMessage{
-						Message: &pb.ServerMessage_Pong{
-							Pong: &pb.HeartbeatMessage{
-								Timestamp: time.Now().UnixNano(),
-							},
-						},
-					}); err != nil {
-						// Connection might be broken, if so terminate goroutine
-						// TODO: we need to do some additional events instead of just termination
-						return
-					}
-				}
 			}
 		}
 	}()
 
 	for {
-		msg, err := stream.Recv()
-		if err == io.EOF {
+		var msg *pb.ClientMessage
+		select {
+		case <-s.draining:
+			stream.Send(&pb.ServerMessage{
+				Message: &pb.ServerMessage_Drain{
+					Drain: &pb.DrainNotice{
+						Reason:           "server is shutting down",
+						ReconnectAfterMs: drainReconnectDelay.Milliseconds(),
+					},
+				},
+			})
+			slog.InfoContext(stream.Context(), "Draining client connection", "session_id", connectionID, "project_id", projectID)
 			return nil
-		}
-		if err != nil {
+
+		case <-heartbeatTicker.C:
+			if isAuthenticated {
+				if err := stream.Send(&pb.ServerMessage{
+					Message: &pb.ServerMessage_Pong{
+						Pong: &pb.HeartbeatMessage{
+							Timestamp: time.Now().UnixNano(),
+						},
+					},
+				}); err != nil {
+					// Connection might be broken, if so terminate the stream
+					// TODO: we need to do some additional events instead of just termination
+					return nil
+				}
+			}
+			continue
+
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
 			return status.Errorf(codes.Internal, "Failed to receive message: %v", err)
+
+		case msg = <-messages:
 		}
 
 		switch m := msg.Message.(type) {
@@ -440,7 +779,19 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 			auth := m.Auth
 			clientID = auth.ClientId
 			projectID = auth.ProjectId
-			if auth.ApiKey == "" || auth.ClientId == "" || auth.ProjectId == "" {
+
+			// clients authenticated by their certificate may leave the API
+			// key and client ID empty, the project owner's client ID is used
+			var accountType string
+			certAuth := false
+			if auth.ApiKey == "" {
+				var certClientID string
+				if accountType, certClientID, certAuth = s.authenticateCert(stream.Context(), auth.ProjectId); certAuth {
+					clientID = certClientID
+				}
+			}
+
+			if !certAuth && (auth.ApiKey == "" || auth.ClientId == "" || auth.ProjectId == "") {
 				var missingFields []string
 				if auth.ApiKey == "" {
 					missingFields = append(missingFields, "api_key")
@@ -462,32 +813,36 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 						},
 					},
 				})
-                fmt.Printf("Unable to find project: clientId=%s projectId=%s (missing: %s)\n",
-                    auth.ClientId, auth.ProjectId, strings.Join(missingFields, ", "))
-                return status.Errorf(codes.Unauthenticated, errorMsg)
-            }
-			var accountType string
-			err := s.db.QueryRow(context.Background(), `
-				SELECT u.account_type 
-				FROM users u 
-				JOIN projects p ON p.user_id = u.id 
-				WHERE p.id = $1 AND p.api_key = $2 AND u.client_id = $3`,
-				auth.ProjectId, auth.ApiKey, auth.ClientId,
-			).Scan(&accountType)
-
-			if err != nil {
-				stream.Send(&pb.ServerMessage{
-					Message: &pb.ServerMessage_AuthResponse{
-						AuthResponse: &pb.AuthResponse{
-							Success: false,
-							Message: "Authentication failed: invalid credentials",
+				slog.InfoContext(stream.Context(), "Rejected client connection", "project_id", auth.ProjectId, "missing", strings.Join(missingFields, ","))
+				return status.Errorf(codes.Unauthenticated, errorMsg)
+			}
+			if !certAuth {
+				err := s.db.QueryRow(stream.Context(), `
+					SELECT u.account_type 
+					FROM users u 
+					JOIN projects p ON p.user_id = u.id 
+					WHERE p.id = $1 AND p.api_key = $2 AND u.client_id = $3`,
+					auth.ProjectId, auth.ApiKey, auth.ClientId,
+				).Scan(&accountType)
+
+				if err != nil {
+					stream.Send(&pb.ServerMessage{
+						Message: &pb.ServerMessage_AuthResponse{
+							AuthResponse: &pb.AuthResponse{
+								Success: false,
+								Message: "Authentication failed: invalid credentials",
+							},
 						},
-					},
-				})
-				fmt.Println("Authentication failed:", err)
-				return status.Errorf(codes.Unauthenticated, "Authentication failed")
+					})
+					slog.InfoContext(stream.Context(), "Client authentication failed", "project_id", auth.ProjectId, "err", err)
+					return status.Errorf(codes.Unauthenticated, "Authentication failed")
+				}
 			}
 
+			if !isAuthenticated {
+				metrics.ConnectSessions.Inc()
+				defer metrics.ConnectSessions.Dec()
+			}
 			connectionID = uuid.New().String()
 			isAuthenticated = true
 			isProAccount = accountType == "pro"
@@ -503,7 +858,7 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 				},
 			})
 
-			fmt.Printf("Client connected: ID=%s, Project=%s\n", clientID, projectID)
+			slog.InfoContext(stream.Context(), "Client connected", "session_id", connectionID, "project_id", projectID, "certificate", certAuth)
 
 		case *pb.ClientMessage_Log:
 			if !isAuthenticated {
@@ -535,11 +890,9 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 			// 	VALUES ($1, $2, $3)`,
 			// 	projectID, m.Log.Category, m.Log.Message,
 			// )
-			fmt.Printf("Received log projectId: %s\n", projectID)
-			fmt.Printf("Received log Category: %s\n", m.Log.Category)
-			fmt.Printf("Received log message: %s\n", m.Log.Message)
+			slog.DebugContext(stream.Context(), "Received log", "session_id", connectionID, "project_id", projectID, "category", m.Log.Category)
 
-			err = nil
+			var err error
 
 			if err != nil {
 				stream.Send(&pb.ServerMessage{
@@ -572,8 +925,7 @@ func (s *LogService) ConnectClient(stream pb.LogService_ConnectClientServer) err
 			})
 
 		case *pb.ClientMessage_Close:
-			fmt.Printf("Client disconnecting: ID=%s, Project=%s, Reason=%s\n",
-				clientID, projectID, m.Close.Reason)
+			slog.InfoContext(stream.Context(), "Client disconnecting", "session_id", connectionID, "project_id", projectID, "reason", m.Close.Reason)
 			return nil
 		}
 	}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/AjayShukla007/logsentinel/internal/ratelimit"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+// batchStream replays logs to BatchSendLogs and keeps the response.
+type batchStream struct {
+	grpc.ServerStream
+	logs     []*pb.LogRequest
+	response *pb.BatchLogResponse
+}
+
+func (b *batchStream) Context() context.Context {
+	return context.Background()
+}
+
+func (b *batchStream) Recv() (*pb.LogRequest, error) {
+	if len(b.logs) == 0 {
+		return nil, io.EOF
+	}
+	req := b.logs[0]
+	b.logs = b.logs[1:]
+	return req, nil
+}
+
+func (b *batchStream) SendAndClose(response *pb.BatchLogResponse) error {
+	b.response = response
+	return nil
+}
+
+func TestSaveBatchCount(t *testing.T) {
+	tests := []struct {
+		name string
+		logs int
+		// drainAfter drains the service once this many logs are saved, zero
+		// never drains
+		drainAfter int
+		// dropped makes save drop every log instead of storing it
+		dropped     bool
+		wantSuccess bool
+		wantCount   int32
+	}{
+		{name: "single log", logs: 1, wantSuccess: true, wantCount: 1},
+		{name: "every log", logs: 5, wantSuccess: true, wantCount: 5},
+		{name: "dropped logs count", logs: 3, dropped: true, wantSuccess: true, wantCount: 3},
+		{name: "shutdown after the first log", logs: 5, drainAfter: 1, wantCount: 1},
+		{name: "shutdown mid batch", logs: 5, drainAfter: 3, wantCount: 3},
+		{name: "shutdown after the last log", logs: 3, drainAfter: 3, wantSuccess: true, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &LogService{
+				rateLimiter: ratelimit.NewRateLimiter(ratelimit.DefaultFreeLogsPerMinute),
+				draining:    make(chan struct{}),
+			}
+
+			stream := &batchStream{}
+			for i := 0; i < tt.logs; i++ {
+				stream.logs = append(stream.logs, &pb.LogRequest{ProjectId: "project", ApiKey: "key", ClientId: "client"})
+			}
+
+			saved := 0
+			save := func(ctx context.Context, req *pb.LogRequest) error {
+				saved++
+				if saved == tt.drainAfter {
+					s.Drain()
+				}
+				if tt.dropped {
+					return errLogFiltered
+				}
+				return nil
+			}
+
+			first, _ := stream.Recv()
+			if err := s.saveBatch(stream, first, "client", true, save); err != nil {
+				t.Fatalf("saveBatch: %v", err)
+			}
+			if stream.response == nil {
+				t.Fatal("no response sent")
+			}
+			if stream.response.Success != tt.wantSuccess {
+				t.Errorf("success = %v, want %v", stream.response.Success, tt.wantSuccess)
+			}
+			if stream.response.Count != tt.wantCount {
+				t.Errorf("count = %d, want %d", stream.response.Count, tt.wantCount)
+			}
+			if int(stream.response.Count) != saved {
+				t.Errorf("count = %d but %d logs were saved, the client would resend %d twice", stream.response.Count, saved, saved-int(stream.response.Count))
+			}
+		})
+	}
+}
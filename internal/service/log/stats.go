@@ -0,0 +1,130 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+const (
+	// maxStatsBuckets caps the buckets of a single GetLogStats response
+	maxStatsBuckets = 2000
+	// targetStatsBuckets is what an automatic bucket size aims for
+	targetStatsBuckets = 120
+)
+
+// statsBucketSizes are the sizes picked when the request leaves it open.
+var statsBucketSizes = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// GetLogStats returns the log volume of a project per category and time
+// bucket. It reads the minute rollups, so the newest counts may lag
+// ingestion by one rollup flush.
+func (s *LogService) GetLogStats(ctx context.Context, req *pb.GetLogStatsRequest) (*pb.LogStats, error) {
+	projectID, err := uuid.Parse(req.ProjectId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid project ID")
+	}
+
+	if _, err := s.authenticate(ctx, req.ProjectId, req.ApiKey, req.ClientId); err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	bucket := time.Duration(req.BucketSeconds) * time.Second
+	switch {
+	case req.BucketSeconds == 0:
+		bucket = pickBucketSize(to.Sub(from))
+	case req.BucketSeconds < 0 || req.BucketSeconds%60 != 0:
+		return nil, status.Error(codes.InvalidArgument, "bucket_seconds must be a positive multiple of 60")
+	}
+
+	// buckets are aligned to a fixed origin so charts of overlapping ranges agree
+	from = alignBucket(from.UTC(), bucket)
+	if to.Sub(from)/bucket > maxStatsBuckets {
+		return nil, status.Errorf(codes.InvalidArgument, "range holds more than %d buckets, use a larger bucket_seconds", maxStatsBuckets)
+	}
+
+	points, err := s.statsRepo.GetStats(ctx, projectID, from, to.UTC(), bucket)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load log stats: %v", err)
+	}
+
+	resp := &pb.LogStats{
+		ProjectId:     req.ProjectId,
+		BucketSeconds: int64(bucket.Seconds()),
+		Totals:        make(map[string]int64),
+	}
+
+	// every bucket is present, empty ones included, so charts need no gap filling
+	byStart := make(map[time.Time]*pb.StatsBucket)
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		b := &pb.StatsBucket{
+			Start:  start.Format(time.RFC3339),
+			Counts: make(map[string]int64),
+		}
+		byStart[start] = b
+		resp.Buckets = append(resp.Buckets, b)
+	}
+
+	for _, p := range points {
+		b, ok := byStart[p.Bucket.UTC()]
+		if !ok {
+			continue
+		}
+		b.Counts[p.Category] += p.Logs
+		b.Total += p.Logs
+		b.Bytes += p.Bytes
+		resp.Totals[p.Category] += p.Logs
+	}
+
+	for _, b := range resp.Buckets {
+		if b.Total > 0 {
+			b.ErrorRate = float64(b.Counts["error"]) / float64(b.Total)
+		}
+	}
+
+	return resp, nil
+}
+
+func alignBucket(t time.Time, bucket time.Duration) time.Time {
+	offset := t.Sub(logstats.Origin)
+	aligned := offset - offset%bucket
+	if offset < 0 && aligned != offset {
+		aligned -= bucket
+	}
+	return logstats.Origin.Add(aligned)
+}
+
+func pickBucketSize(span time.Duration) time.Duration {
+	for _, size := range statsBucketSizes {
+		if span/size <= targetStatsBuckets {
+			return size
+		}
+	}
+	return statsBucketSizes[len(statsBucketSizes)-1]
+}
@@ -0,0 +1,357 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/category"
+	"github.com/AjayShukla007/logsentinel/internal/pipeline"
+	"github.com/AjayShukla007/logsentinel/internal/redact"
+	"github.com/AjayShukla007/logsentinel/internal/repository/processing"
+	"github.com/AjayShukla007/logsentinel/internal/service/authz"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+type ProcessingService struct {
+	pb.UnimplementedProcessingServiceServer
+	repo       processing.Repository
+	owners     *authz.ProjectOwners
+	engine     *pipeline.Engine
+	redactor   *redact.Redactor
+	categories *category.Registry
+}
+
+func NewProcessingService(repo processing.Repository, owners *authz.ProjectOwners, engine *pipeline.Engine, redactor *redact.Redactor, categories *category.Registry) *ProcessingService {
+	return &ProcessingService{
+		repo:       repo,
+		owners:     owners,
+		engine:     engine,
+		redactor:   redactor,
+		categories: categories,
+	}
+}
+
+func (s *ProcessingService) CreateProcessingRule(ctx context.Context, req *pb.SaveProcessingRuleRequest) (*pb.ProcessingRule, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	projectID, _, err := s.owners.Authorize(ctx, req.Rule.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.toRule(projectID, req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = uuid.New()
+	rule.ProjectID = projectID
+
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create processing rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return toProcessingRule(rule), nil
+}
+
+func (s *ProcessingService) UpdateProcessingRule(ctx context.Context, req *pb.SaveProcessingRuleRequest) (*pb.ProcessingRule, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	projectID, _, err := s.owners.Authorize(ctx, req.Rule.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(req.Rule.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule ID")
+	}
+
+	rule, err := s.toRule(projectID, req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = ruleID
+	rule.ProjectID = projectID
+
+	err = s.repo.UpdateRule(ctx, rule)
+	if errors.Is(err, processing.ErrRuleNotFound) {
+		return nil, status.Error(codes.NotFound, "processing rule not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update processing rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return toProcessingRule(rule), nil
+}
+
+func (s *ProcessingService) DeleteProcessingRule(ctx context.Context, req *pb.DeleteProcessingRuleRequest) (*pb.DeleteProcessingRuleResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(req.RuleId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule ID")
+	}
+
+	err = s.repo.DeleteRule(ctx, projectID, ruleID)
+	if errors.Is(err, processing.ErrRuleNotFound) {
+		return nil, status.Error(codes.NotFound, "processing rule not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete processing rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return &pb.DeleteProcessingRuleResponse{
+		Success: true,
+		Message: "Processing rule deleted successfully",
+	}, nil
+}
+
+func (s *ProcessingService) ListProcessingRules(ctx context.Context, req *pb.ListProcessingRulesRequest) (*pb.ListProcessingRulesResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.ListRules(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list processing rules: %v", err)
+	}
+
+	format, err := s.repo.GetBodyFormat(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load body format: %v", err)
+	}
+
+	resp := &pb.ListProcessingRulesResponse{GrokPatterns: pipeline.GrokPatterns(), BodyFormat: format}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, toProcessingRule(rule))
+	}
+	return resp, nil
+}
+
+// DryRunProcessingRules shows what parsing and the rules make of a sample
+// log without storing anything. Without rules or a body format in the
+// request the stored ones of the project are used. Disabled rules are
+// skipped either way.
+func (s *ProcessingService) DryRunProcessingRules(ctx context.Context, req *pb.DryRunProcessingRulesRequest) (*pb.DryRunProcessingRulesResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*processing.Rule
+	if len(req.Rules) > 0 {
+		for _, r := range req.Rules {
+			rule, err := s.toRule(projectID, r)
+			if err != nil {
+				return nil, err
+			}
+			rule.ID, _ = uuid.Parse(r.Id)
+			rule.ProjectID = projectID
+			rules = append(rules, rule)
+		}
+	} else {
+		rules, err = s.repo.ListRules(ctx, projectID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to list processing rules: %v", err)
+		}
+	}
+
+	format := req.BodyFormat
+	if format == "" {
+		format, err = s.repo.GetBodyFormat(ctx, projectID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to load body format: %v", err)
+		}
+	} else if !pipeline.ValidFormat(format) {
+		return nil, status.Error(codes.InvalidArgument, "body_format must be none, auto, json or logfmt")
+	}
+
+	outcome, err := s.engine.DryRun(projectID, format, rules, pipeline.Log{
+		Category: req.Category,
+		Message:  req.Message,
+		Metadata: req.Metadata,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rule: %v", err)
+	}
+
+	resp := &pb.DryRunProcessingRulesResponse{
+		Dropped:   outcome.Dropped,
+		DroppedBy: outcome.DroppedBy,
+		Category:  outcome.Log.Category,
+		Message:   outcome.Log.Message,
+		Metadata:  outcome.Log.Metadata,
+		Tags:      outcome.Log.Tags,
+		TraceId:   outcome.Log.TraceID,
+	}
+	if !outcome.Log.Time.IsZero() {
+		resp.Time = outcome.Log.Time.Format(time.RFC3339Nano)
+	}
+	for _, step := range outcome.Steps {
+		if step.Rule == nil {
+			resp.Steps = append(resp.Steps, &pb.ProcessingStep{
+				Action:  "parse",
+				Matched: step.Matched,
+				Note:    step.Note,
+			})
+			continue
+		}
+		resp.Steps = append(resp.Steps, &pb.ProcessingStep{
+			RuleId:   step.Rule.ID.String(),
+			RuleName: step.Rule.Name,
+			Action:   step.Rule.Action,
+			Matched:  step.Matched,
+			Note:     step.Note,
+		})
+	}
+	if outcome.Dropped {
+		return resp, nil
+	}
+
+	// ingestion redacts the processed log before storing it
+	redacted := s.redactor.Preview(projectID, outcome.Log.Message, outcome.Log.Metadata)
+	resp.Steps = append(resp.Steps, &pb.ProcessingStep{
+		Action:  "redact",
+		Matched: len(redacted.Counts) > 0,
+		Note:    redactionNote(redacted),
+	})
+	if redacted.Dropped {
+		resp.Dropped, resp.DroppedBy = true, redacted.DroppedBy
+		resp.Message, resp.Metadata = "", nil
+		return resp, nil
+	}
+	resp.Message, resp.Metadata = redacted.Message, redacted.Metadata
+	return resp, nil
+}
+
+// redactionNote lists how many values each detector found.
+func redactionNote(result redact.Result) string {
+	if result.Dropped {
+		return "dropped by detector " + result.DroppedBy
+	}
+	detectors := make([]string, 0, len(result.Counts))
+	for name := range result.Counts {
+		detectors = append(detectors, name)
+	}
+	sort.Strings(detectors)
+
+	found := make([]string, len(detectors))
+	for i, name := range detectors {
+		found[i] = fmt.Sprintf("%s: %d", name, result.Counts[name])
+	}
+	return strings.Join(found, ", ")
+}
+
+// SetBodyFormat sets how the message bodies of the project are parsed.
+func (s *ProcessingService) SetBodyFormat(ctx context.Context, req *pb.SetBodyFormatRequest) (*pb.SetBodyFormatResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pipeline.ValidFormat(req.BodyFormat) {
+		return nil, status.Error(codes.InvalidArgument, "body_format must be none, auto, json or logfmt")
+	}
+
+	err = s.repo.SetBodyFormat(ctx, projectID, req.BodyFormat)
+	if errors.Is(err, processing.ErrProjectNotFound) {
+		return nil, status.Error(codes.NotFound, "project not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set body format: %v", err)
+	}
+
+	s.reload(ctx)
+	return &pb.SetBodyFormatResponse{
+		Success: true,
+		Message: "Body format updated successfully",
+	}, nil
+}
+
+// reload applies the change on this replica right away, other replicas
+// pick it up on their next periodic reload.
+func (s *ProcessingService) reload(ctx context.Context) {
+	if err := s.engine.Reload(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error reloading processing rules", "err", err)
+	}
+}
+
+// toRule checks a rule of the project. Categories are normalized, rename
+// targets must be a category of the project.
+func (s *ProcessingService) toRule(projectID uuid.UUID, r *pb.ProcessingRule) (*processing.Rule, error) {
+	if r.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if r.Position < 0 {
+		return nil, status.Error(codes.InvalidArgument, "position must not be negative")
+	}
+
+	matchCategory, target := r.MatchCategory, r.Target
+	if matchCategory != "" {
+		matchCategory = category.Normalize(matchCategory)
+	}
+	if r.Action == processing.ActionRename {
+		var err error
+		if target, err = s.categories.Resolve(projectID, target); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid target: %v", err)
+		}
+	}
+
+	rule := &processing.Rule{
+		Position:      int(r.Position),
+		Name:          r.Name,
+		Enabled:       r.Enabled,
+		MatchCategory: matchCategory,
+		MatchPattern:  r.MatchPattern,
+		Action:        r.Action,
+		SamplePercent: r.SamplePercent,
+		Pattern:       r.Pattern,
+		Replacement:   r.Replacement,
+		Target:        target,
+		Tags:          r.Tags,
+	}
+	if err := pipeline.Validate(rule); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return rule, nil
+}
+
+func toProcessingRule(rule *processing.Rule) *pb.ProcessingRule {
+	return &pb.ProcessingRule{
+		Id:            rule.ID.String(),
+		ProjectId:     rule.ProjectID.String(),
+		Position:      int32(rule.Position),
+		Name:          rule.Name,
+		Enabled:       rule.Enabled,
+		MatchCategory: rule.MatchCategory,
+		MatchPattern:  rule.MatchPattern,
+		Action:        rule.Action,
+		SamplePercent: rule.SamplePercent,
+		Pattern:       rule.Pattern,
+		Replacement:   rule.Replacement,
+		Target:        rule.Target,
+		Tags:          rule.Tags,
+		CreatedAt:     rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,10 +2,19 @@ package project
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/AjayShukla007/logsentinel/internal/category"
+	categoryrepo "github.com/AjayShukla007/logsentinel/internal/repository/category"
+	"github.com/AjayShukla007/logsentinel/internal/repository/clientidentity"
 	"github.com/AjayShukla007/logsentinel/internal/repository/project"
+	"github.com/AjayShukla007/logsentinel/internal/repository/retention"
 	userrepo "github.com/AjayShukla007/logsentinel/internal/repository/user"
+	"github.com/AjayShukla007/logsentinel/internal/service/authz"
 	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -14,14 +23,27 @@ import (
 
 type ProjectService struct {
 	pb.UnimplementedProjectServiceServer
-	repo     project.Repository
-	userRepo userrepo.Repository
+	repo          project.Repository
+	userRepo      userrepo.Repository
+	retentionRepo retention.Repository
+	owners        *authz.ProjectOwners
+	categoryRepo  categoryrepo.Repository
+	categories    *category.Registry
+	identityRepo  clientidentity.Repository
 }
 
-func NewProjectService(repo project.Repository, userRepo userrepo.Repository) *ProjectService {
+// maxClientIdentities bounds the client certificate identities of a project
+const maxClientIdentities = 20
+
+func NewProjectService(repo project.Repository, userRepo userrepo.Repository, retentionRepo retention.Repository, owners *authz.ProjectOwners, categoryRepo categoryrepo.Repository, categories *category.Registry, identityRepo clientidentity.Repository) *ProjectService {
 	return &ProjectService{
-		repo:     repo,
-		userRepo: userRepo,
+		repo:          repo,
+		userRepo:      userRepo,
+		retentionRepo: retentionRepo,
+		owners:        owners,
+		categoryRepo:  categoryRepo,
+		categories:    categories,
+		identityRepo:  identityRepo,
 	}
 }
 
@@ -59,3 +81,206 @@ func (s *ProjectService) DeleteProject(ctx context.Context, req *pb.DeleteProjec
 		Message: "Project deleted successfully (demo mode)",
 	}, nil
 }
+
+func (s *ProjectService) GetRetentionPolicy(ctx context.Context, req *pb.GetRetentionPolicyRequest) (*pb.RetentionPolicy, error) {
+	projectID, accountType, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.retentionPolicy(ctx, projectID, accountType)
+}
+
+func (s *ProjectService) SetRetentionPolicy(ctx context.Context, req *pb.SetRetentionPolicyRequest) (*pb.RetentionPolicy, error) {
+	projectID, accountType, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	planPolicies, err := s.retentionRepo.GetPlanPolicies(ctx, accountType)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load plan retention: %v", err)
+	}
+	maxDays := retention.MaxDays(planPolicies)
+
+	seen := make(map[string]bool)
+	policies := make([]retention.Policy, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		name := retention.AllCategories
+		if rule.Category != retention.AllCategories {
+			if name, err = s.categories.Resolve(projectID, rule.Category); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		if rule.RetentionDays < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "retention_days for category %q must not be negative", rule.Category)
+		}
+		if !retention.Allowed(int(rule.RetentionDays), maxDays) {
+			return nil, status.Errorf(codes.InvalidArgument, "retention_days for category %q must be between 1 and the %s plan maximum of %d", rule.Category, accountType, maxDays)
+		}
+		if seen[name] {
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate rule for category %q", rule.Category)
+		}
+		seen[name] = true
+		policies = append(policies, retention.Policy{
+			Category:      name,
+			RetentionDays: int(rule.RetentionDays),
+		})
+	}
+
+	if err := s.retentionRepo.ReplaceProjectPolicies(ctx, projectID, policies); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save retention policy: %v", err)
+	}
+
+	return s.retentionPolicy(ctx, projectID, accountType)
+}
+
+func (s *ProjectService) GetProjectCategories(ctx context.Context, req *pb.GetProjectCategoriesRequest) (*pb.ProjectCategories, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectCategories(ctx, projectID)
+}
+
+// SetProjectCategories replaces the categories logs of the project can use,
+// no categories restores the defaults.
+func (s *ProjectService) SetProjectCategories(ctx context.Context, req *pb.SetProjectCategoriesRequest) (*pb.ProjectCategories, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := category.Validate(req.Categories)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.categoryRepo.SetCategories(ctx, projectID, names); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save categories: %v", err)
+	}
+
+	// other replicas pick the change up on their next periodic reload
+	if err := s.categories.Reload(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error reloading categories", "err", err)
+	}
+
+	return s.projectCategories(ctx, projectID)
+}
+
+func (s *ProjectService) projectCategories(ctx context.Context, projectID uuid.UUID) (*pb.ProjectCategories, error) {
+	names, err := s.categoryRepo.ListCategories(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load categories: %v", err)
+	}
+
+	resp := &pb.ProjectCategories{
+		ProjectId:  projectID.String(),
+		Categories: names,
+		Aliases:    category.Aliases(),
+	}
+	if len(names) == 0 {
+		resp.Categories, resp.Defaults = category.Defaults, true
+	}
+	return resp, nil
+}
+
+func (s *ProjectService) GetClientIdentities(ctx context.Context, req *pb.GetClientIdentitiesRequest) (*pb.ClientIdentities, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.clientIdentities(ctx, projectID)
+}
+
+// SetClientIdentities replaces the client certificate identities that can
+// send logs to the project over mutual TLS without an API key.
+func (s *ProjectService) SetClientIdentities(ctx context.Context, req *pb.SetClientIdentitiesRequest) (*pb.ClientIdentities, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := validateIdentities(req.Identities)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.identityRepo.SetIdentities(ctx, projectID, identities)
+	if errors.Is(err, clientidentity.ErrIdentityTaken) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save client identities: %v", err)
+	}
+
+	return s.clientIdentities(ctx, projectID)
+}
+
+func (s *ProjectService) clientIdentities(ctx context.Context, projectID uuid.UUID) (*pb.ClientIdentities, error) {
+	identities, err := s.identityRepo.ListIdentities(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load client identities: %v", err)
+	}
+
+	return &pb.ClientIdentities{
+		ProjectId:  projectID.String(),
+		Identities: identities,
+	}, nil
+}
+
+func validateIdentities(identities []string) ([]string, error) {
+	if len(identities) > maxClientIdentities {
+		return nil, fmt.Errorf("at most %d client identities are allowed", maxClientIdentities)
+	}
+
+	seen := make(map[string]bool, len(identities))
+	var valid []string
+	for _, identity := range identities {
+		identity = strings.TrimSpace(identity)
+		if identity == "" || len(identity) > 255 {
+			return nil, fmt.Errorf("client identities must be 1 to 255 characters")
+		}
+		if seen[identity] {
+			continue
+		}
+		seen[identity] = true
+		valid = append(valid, identity)
+	}
+	return valid, nil
+}
+
+func (s *ProjectService) retentionPolicy(ctx context.Context, projectID uuid.UUID, accountType string) (*pb.RetentionPolicy, error) {
+	planPolicies, err := s.retentionRepo.GetPlanPolicies(ctx, accountType)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load plan retention: %v", err)
+	}
+
+	projectPolicies, err := s.retentionRepo.GetProjectPolicies(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load project retention: %v", err)
+	}
+
+	effective := retention.Resolve(projectID, accountType, planPolicies, projectPolicies)
+
+	return &pb.RetentionPolicy{
+		ProjectId:      projectID.String(),
+		AccountType:    accountType,
+		PlanRules:      toRetentionRules(planPolicies),
+		ProjectRules:   toRetentionRules(projectPolicies),
+		EffectiveRules: toRetentionRules(effective.Policies()),
+	}, nil
+}
+
+func toRetentionRules(policies []retention.Policy) []*pb.RetentionRule {
+	rules := make([]*pb.RetentionRule, 0, len(policies))
+	for _, p := range policies {
+		rules = append(rules, &pb.RetentionRule{
+			Category:      p.Category,
+			RetentionDays: int32(p.RetentionDays),
+		})
+	}
+	return rules
+}
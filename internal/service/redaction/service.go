@@ -0,0 +1,188 @@
+package redaction
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/AjayShukla007/logsentinel/internal/redact"
+	"github.com/AjayShukla007/logsentinel/internal/repository/redaction"
+	"github.com/AjayShukla007/logsentinel/internal/service/authz"
+	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
+)
+
+type RedactionService struct {
+	pb.UnimplementedRedactionServiceServer
+	repo     redaction.Repository
+	owners   *authz.ProjectOwners
+	redactor *redact.Redactor
+}
+
+func NewRedactionService(repo redaction.Repository, owners *authz.ProjectOwners, redactor *redact.Redactor) *RedactionService {
+	return &RedactionService{
+		repo:     repo,
+		owners:   owners,
+		redactor: redactor,
+	}
+}
+
+// SaveRedactionRule adds a custom rule or overrides a built-in detector,
+// saving a built-in override again replaces the previous one.
+func (s *RedactionService) SaveRedactionRule(ctx context.Context, req *pb.SaveRedactionRuleRequest) (*pb.RedactionRule, error) {
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	projectID, _, err := s.owners.Authorize(ctx, req.Rule.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &redaction.Rule{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Detector:  req.Rule.Detector,
+		Name:      req.Rule.Name,
+		Pattern:   req.Rule.Pattern,
+		Action:    req.Rule.Action,
+		Enabled:   req.Rule.Enabled,
+	}
+
+	if !redact.ValidAction(rule.Action) {
+		return nil, status.Error(codes.InvalidArgument, "action must be mask, hash or drop")
+	}
+	switch {
+	case rule.Detector == redaction.DetectorCustom:
+		if rule.Name == "" {
+			return nil, status.Error(codes.InvalidArgument, "name is required for custom rules")
+		}
+		if rule.Pattern == "" {
+			return nil, status.Error(codes.InvalidArgument, "pattern is required for custom rules")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid pattern: %v", err)
+		}
+	case redact.IsBuiltin(rule.Detector):
+		if rule.Pattern != "" {
+			return nil, status.Error(codes.InvalidArgument, "built-in detectors take no pattern")
+		}
+		rule.Name = rule.Detector
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "detector must be custom or one of the built-in detectors %v", redact.Builtins())
+	}
+
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save redaction rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return toRedactionRule(rule), nil
+}
+
+func (s *RedactionService) DeleteRedactionRule(ctx context.Context, req *pb.DeleteRedactionRuleRequest) (*pb.DeleteRedactionRuleResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := uuid.Parse(req.RuleId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule ID")
+	}
+
+	err = s.repo.DeleteRule(ctx, projectID, ruleID)
+	if errors.Is(err, redaction.ErrRuleNotFound) {
+		return nil, status.Error(codes.NotFound, "redaction rule not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete redaction rule: %v", err)
+	}
+
+	s.reload(ctx)
+	return &pb.DeleteRedactionRuleResponse{
+		Success: true,
+		Message: "Redaction rule deleted successfully",
+	}, nil
+}
+
+func (s *RedactionService) ListRedactionRules(ctx context.Context, req *pb.ListRedactionRulesRequest) (*pb.ListRedactionRulesResponse, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.ListRules(ctx, projectID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list redaction rules: %v", err)
+	}
+
+	resp := &pb.ListRedactionRulesResponse{BuiltinDetectors: redact.Builtins()}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, toRedactionRule(rule))
+	}
+	return resp, nil
+}
+
+// GetRedactionStats returns how many values each detector redacted in the
+// project, counted per UTC day.
+func (s *RedactionService) GetRedactionStats(ctx context.Context, req *pb.GetRedactionStatsRequest) (*pb.RedactionStats, error) {
+	projectID, _, err := s.owners.Authorize(ctx, req.ProjectId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse(time.RFC3339, req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	counts, err := s.repo.GetCounts(ctx, projectID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load redaction stats: %v", err)
+	}
+
+	resp := &pb.RedactionStats{}
+	for _, c := range counts {
+		resp.Counts = append(resp.Counts, &pb.RedactionCount{
+			Detector: c.Detector,
+			Action:   c.Action,
+			Count:    c.Count,
+		})
+		resp.Total += c.Count
+	}
+	return resp, nil
+}
+
+// reload applies the change on this replica right away, other replicas
+// pick it up on their next periodic reload.
+func (s *RedactionService) reload(ctx context.Context) {
+	if err := s.redactor.Reload(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error reloading redaction rules", "err", err)
+	}
+}
+
+func toRedactionRule(rule *redaction.Rule) *pb.RedactionRule {
+	return &pb.RedactionRule{
+		Id:        rule.ID.String(),
+		ProjectId: rule.ProjectID.String(),
+		Detector:  rule.Detector,
+		Name:      rule.Name,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		Enabled:   rule.Enabled,
+		CreatedAt: rule.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -2,23 +2,38 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/ratelimit"
 	userrepo "github.com/AjayShukla007/logsentinel/internal/repository/user"
 	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
 	"github.com/google/uuid"
-	// "google.golang.org/grpc/codes"
-	// "google.golang.org/grpc/status"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Project limits per account type, pro accounts have no log rate limit
+const (
+	freeProjectLimit = 5
+	proProjectLimit  = 50
 )
 
 type UserService struct {
 	pb.UnimplementedUserServiceServer
-	repo userrepo.Repository
+	repo        userrepo.Repository
+	notifier    *notify.Dispatcher
+	rateLimiter *ratelimit.RateLimiter
 }
 
-func NewUserService(repo userrepo.Repository) *UserService {
+func NewUserService(repo userrepo.Repository, notifier *notify.Dispatcher, rateLimiter *ratelimit.RateLimiter) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:        repo,
+		notifier:    notifier,
+		rateLimiter: rateLimiter,
 	}
 }
 
@@ -82,12 +97,56 @@ func (s *UserService) GetUserProjectCount(ctx context.Context, userID string) (i
 }
 
 func (s *UserService) CheckUserQuota(ctx context.Context, req *pb.GetUserRequest) (*pb.QuotaResponse, error) {
-	return &pb.QuotaResponse{
-		AccountType:        "pro",
-		ProjectCount:       int32(3),
-		ProjectLimit:       5,
-		LogCountLastMinute: int32(10),
-		LogLimitPerMinute:  100,
-		LastLogTime:        time.Now().Format(time.RFC3339),
-	}, nil
+	user, err := s.repo.GetByUserID(ctx, req.UserId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load user: %v", err)
+	}
+
+	usage, err := s.repo.GetQuota(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load usage: %v", err)
+	}
+
+	quota := &pb.QuotaResponse{
+		AccountType:        user.AccountType,
+		ProjectCount:       int32(usage.ProjectCount),
+		ProjectLimit:       freeProjectLimit,
+		LogCountLastMinute: int32(usage.LogCountLastMinute),
+		LogLimitPerMinute:  int32(s.rateLimiter.FreeLogsPerMinute()),
+	}
+	if user.AccountType == "pro" {
+		quota.ProjectLimit = proProjectLimit
+		quota.LogLimitPerMinute = 0
+	}
+	if usage.LastLogTime != nil {
+		quota.LastLogTime = usage.LastLogTime.Format(time.RFC3339)
+	}
+
+	s.notifyQuota(req.UserId, quota)
+	return quota, nil
+}
+
+// notifyQuota raises a quota event when a user reached one of their limits.
+func (s *UserService) notifyQuota(userID string, quota *pb.QuotaResponse) {
+	fields := map[string]string{"account_type": quota.AccountType}
+	if quota.ProjectLimit > 0 && quota.ProjectCount >= quota.ProjectLimit {
+		fields["projects"] = fmt.Sprintf("%d of %d", quota.ProjectCount, quota.ProjectLimit)
+	}
+	if quota.LogLimitPerMinute > 0 && quota.LogCountLastMinute >= quota.LogLimitPerMinute {
+		fields["logs_per_minute"] = fmt.Sprintf("%d of %d", quota.LogCountLastMinute, quota.LogLimitPerMinute)
+	}
+	if len(fields) == 1 {
+		return
+	}
+
+	s.notifier.Notify(notify.Event{
+		Kind:     notify.KindQuotaExceeded,
+		Severity: notify.SeverityWarning,
+		Title:    "User reached their quota",
+		UserID:   userID,
+		Fields:   fields,
+	})
 }
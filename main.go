@@ -2,164 +2,555 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"flag"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/AjayShukla007/logsentinel/internal/alerting"
+	"github.com/AjayShukla007/logsentinel/internal/anomaly"
+	"github.com/AjayShukla007/logsentinel/internal/archive"
+	"github.com/AjayShukla007/logsentinel/internal/category"
+	"github.com/AjayShukla007/logsentinel/internal/certs"
+	"github.com/AjayShukla007/logsentinel/internal/clustering"
+	"github.com/AjayShukla007/logsentinel/internal/config"
+	"github.com/AjayShukla007/logsentinel/internal/db"
+	"github.com/AjayShukla007/logsentinel/internal/health"
+	"github.com/AjayShukla007/logsentinel/internal/logging"
+	"github.com/AjayShukla007/logsentinel/internal/metrics"
+	"github.com/AjayShukla007/logsentinel/internal/notify"
+	"github.com/AjayShukla007/logsentinel/internal/pipeline"
+	"github.com/AjayShukla007/logsentinel/internal/ratelimit"
+	"github.com/AjayShukla007/logsentinel/internal/redact"
+	"github.com/AjayShukla007/logsentinel/internal/rehydrate"
 	userrepo "github.com/AjayShukla007/logsentinel/internal/repository/user"
+	"github.com/AjayShukla007/logsentinel/internal/rollup"
+	"github.com/AjayShukla007/logsentinel/internal/scheduler"
+	adminservice "github.com/AjayShukla007/logsentinel/internal/service/admin"
+	alertservice "github.com/AjayShukla007/logsentinel/internal/service/alert"
+	"github.com/AjayShukla007/logsentinel/internal/service/authz"
 	cronservice "github.com/AjayShukla007/logsentinel/internal/service/cron"
 	logservice "github.com/AjayShukla007/logsentinel/internal/service/log"
+	processingservice "github.com/AjayShukla007/logsentinel/internal/service/processing"
 	projectservice "github.com/AjayShukla007/logsentinel/internal/service/project"
+	redactionservice "github.com/AjayShukla007/logsentinel/internal/service/redaction"
 	userservice "github.com/AjayShukla007/logsentinel/internal/service/user"
+	"github.com/AjayShukla007/logsentinel/internal/tracing"
 
 	// teamservice "github.com/AjayShukla007/logsentinel/internal/service/team"
 
+	alertrepo "github.com/AjayShukla007/logsentinel/internal/repository/alert"
+	anomalyrepo "github.com/AjayShukla007/logsentinel/internal/repository/anomaly"
+	categoryrepo "github.com/AjayShukla007/logsentinel/internal/repository/category"
+	clientidentityrepo "github.com/AjayShukla007/logsentinel/internal/repository/clientidentity"
+	jobrunrepo "github.com/AjayShukla007/logsentinel/internal/repository/jobrun"
+	logstatsrepo "github.com/AjayShukla007/logsentinel/internal/repository/logstats"
+	patternrepo "github.com/AjayShukla007/logsentinel/internal/repository/pattern"
+	processingrepo "github.com/AjayShukla007/logsentinel/internal/repository/processing"
 	projectrepo "github.com/AjayShukla007/logsentinel/internal/repository/project"
+	redactionrepo "github.com/AjayShukla007/logsentinel/internal/repository/redaction"
+	rehydrationrepo "github.com/AjayShukla007/logsentinel/internal/repository/rehydration"
+	retentionrepo "github.com/AjayShukla007/logsentinel/internal/repository/retention"
 	// teamrepo "github.com/AjayShukla007/logsentinel/internal/repository/team"
 
 	pb "github.com/AjayShukla007/logsentinel/proto/gen/proto"
 )
 
-const (
-	port = ":50051"
-)
+// fatal logs an error and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
 
-func getDatabaseURL() string {
-    host := getEnvWithDefault("DB_HOST", "db")
-    dbPortStr := getEnvWithDefault("DB_PORT", "5432")
-    dbPort, err := strconv.Atoi(dbPortStr)
-    if err != nil {
-        log.Fatalf("Invalid DB_PORT: %v", err)
-    }
-    user := getEnvWithDefault("DB_USER", "postgres")
-    dbName := getEnvWithDefault("DB_NAME", "logsentinel")
-
-    passwordBytes, err := os.ReadFile("/run/secrets/db-password")
-    if err != nil {
-        log.Fatalf("failed to read database password: %v", err)
-    }
-    password := strings.TrimSpace(string(passwordBytes))
-
-    dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-        user, password, host, dbPort, dbName)
-
-    log.Printf("Connecting to database at %s:%d", host, dbPort)
-    return dsn
+// loadConfig loads the configuration from the flags in args, the
+// environment and the config file and returns the arguments left after the
+// flags. It sets up the server log and exits when the configuration is
+// invalid.
+func loadConfig(name string, args []string) (*config.Config, []string) {
+	cfg, rest, err := config.Load(name, args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fatal("Invalid configuration", "err", err)
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		fatal("Invalid log configuration", "err", err)
+	}
+	return cfg, rest
 }
 
-func getEnvWithDefault(key, defaultValue string) string {
-    value := os.Getenv(key)
-    if value == "" {
-        return defaultValue
-    }
-    return value
+// connectDatabase opens the connection pool and checks that the database
+// answers.
+func connectDatabase(cfg config.DatabaseConfig) *pgxpool.Pool {
+	poolConfig, err := cfg.PoolConfig()
+	if err != nil {
+		fatal("Invalid database configuration", "err", err)
+	}
+
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+
+	slog.Info("Connecting to database", "address", cfg.Address(), "sslmode", cfg.SSLMode)
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		fatal("Unable to create connection pool", "err", err)
+	}
+
+	var version string
+	err = dbpool.QueryRow(context.Background(), "SELECT version()").Scan(&version)
+	if err != nil {
+		fatal("Failed to query database", "err", err)
+	}
+	slog.Info("Connected to PostgreSQL", "version", version)
+	return dbpool
 }
 
-func getLocalDatabaseURL() string {
-	host := os.Getenv("LOCAL_DB_HOST")
-	if host == "" {
-		host = "localhost"
+// startTracing sets up the trace exporter, trace context of clients is
+// propagated even when spans aren't exported.
+func startTracing(cfg config.TracingConfig) *tracing.Provider {
+	tracingConfig := tracing.DefaultConfig()
+	tracingConfig.Exporter = cfg.Exporter
+	tracingConfig.Endpoint = cfg.Endpoint
+	tracingConfig.Insecure = cfg.Insecure
+	tracingConfig.SampleRatio = cfg.SampleRatio
+	tracingConfig.ServiceName = cfg.ServiceName
+
+	traces, err := tracing.NewProvider(context.Background(), tracingConfig)
+	if err != nil {
+		fatal("Unable to start tracing", "err", err)
+	}
+	if cfg.Exporter != tracing.ExporterNone {
+		slog.Info("Tracing enabled", "exporter", cfg.Exporter, "sample_ratio", cfg.SampleRatio)
 	}
+	return traces
+}
 
-	portStr := os.Getenv("LOCAL_DB_PORT")
-	if portStr == "" {
-		portStr = "5432"
+func getCronConfig(cfg config.RetentionConfig) cronservice.Config {
+	cronConfig := cronservice.DefaultConfig()
+	cronConfig.RetentionSchedule = cfg.Schedule
+	cronConfig.PartitionSchedule = cfg.PartitionSchedule
+	cronConfig.BatchSize = cfg.BatchSize
+	cronConfig.BatchPause = cfg.BatchPause
+	cronConfig.MaxRunTime = cfg.MaxRunTime
+	cronConfig.PartitionInterval = cfg.PartitionInterval
+	cronConfig.PartitionsAhead = cfg.PartitionsAhead
+	cronConfig.ArchivePlans = cfg.ArchivePlans
+	return cronConfig
+}
+
+// getArchiver builds the cold archive used by the retention cleanup, it
+// returns nil when archiving is disabled.
+func getArchiver(cfg config.ArchiveConfig) *archive.Archiver {
+	if !cfg.Enabled {
+		return nil
 	}
 
-	port, err := strconv.Atoi(portStr)
+	store, err := archive.NewStore(archive.StoreConfig{
+		Backend:     cfg.Backend,
+		LocalDir:    cfg.Dir,
+		S3Endpoint:  cfg.S3Endpoint,
+		S3Bucket:    cfg.S3Bucket,
+		S3Region:    cfg.S3Region,
+		S3AccessKey: cfg.S3AccessKey,
+		S3SecretKey: cfg.S3SecretKey,
+		S3UseSSL:    cfg.S3UseSSL,
+	})
 	if err != nil {
-		log.Fatalf("Invalid LOCAL_DB_PORT: %v", err)
+		fatal("Unable to create archive store", "err", err)
 	}
 
-	user := os.Getenv("LOCAL_DB_USER")
-	if user == "" {
-		user = "postgres"
+	archiver, err := archive.NewArchiver(store, cfg.Format, cfg.Prefix)
+	if err != nil {
+		fatal("Invalid archive configuration", "err", err)
 	}
 
-	password := os.Getenv("LOCAL_DB_PASSWORD")
-	if password == "" {
-		password = "postgres"
+	slog.Info("Cold archive of expired logs enabled", "backend", cfg.Backend)
+	return archiver
+}
+
+// getNotifier builds the configured notification channels, without any
+// channel events are simply dropped.
+func getNotifier(cfg config.NotifyConfig) *notify.Dispatcher {
+	templates, err := notify.LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		fatal("Invalid notification templates", "err", err)
 	}
 
-	dbName := os.Getenv("LOCAL_DB_NAME")
-	if dbName == "" {
-		dbName = "logsentinel"
+	var notifiers []notify.Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier("webhook", cfg.WebhookURL, cfg.WebhookSecret))
 	}
+	if cfg.SlackWebhookURL != "" {
+		notifiers = append(notifiers, notify.NewSlackNotifier("slack", cfg.SlackWebhookURL))
+	}
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, notify.NewSMTPNotifier("email", notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			To:       cfg.SMTPTo,
+		}))
+	}
+
+	notifyConfig := notify.DefaultConfig()
+	notifyConfig.MaxAttempts = cfg.MaxAttempts
+	notifyConfig.RatePerMinute = cfg.RatePerMinute
+	notifyConfig.Burst = cfg.Burst
+	notifyConfig.Cooldown = cfg.Cooldown
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		user, password, host, port, dbName)
+	dispatcher := notify.NewDispatcher(templates, notifyConfig, notifiers...)
+	if channels := dispatcher.Channels(); len(channels) > 0 {
+		slog.Info("Notification channels enabled", "channels", strings.Join(channels, ","))
+	}
+	return dispatcher
+}
 
-	log.Printf("Connecting to local database at %s:%d", host, port)
-	return dsn
+// getRedactConfig returns the redaction defaults, disabled built-in
+// detectors stay off unless a project enables them.
+func getRedactConfig(cfg config.RedactConfig) redact.Config {
+	redactConfig := redact.DefaultConfig()
+	redactConfig.DefaultAction = cfg.DefaultAction
+	redactConfig.Disabled = cfg.Disabled
+	redactConfig.HashKey = []byte(cfg.HashKey)
+	return redactConfig
 }
 
 func main() {
-    log.Println("Starting LogSentinel server...")
-    err := godotenv.Load()
-    if err != nil {
-        log.Println("Warning: Error loading .env file, using defaults")
-    }
-	var dsn string
-    if os.Getenv("DB_HOST") != "" {
-        log.Println("Initializing Docker/Compose database connection...")
-        dsn = getDatabaseURL()
-    } else {
-        log.Println("Initializing local database connection...")
-        dsn = getLocalDatabaseURL()
-    }
-    dbpool, err := pgxpool.New(context.Background(), dsn)
-	if err != nil {
-		log.Fatalf("Unable to create connection pool: %v\n", err)
+	envErr := godotenv.Load()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "config":
+			os.Exit(runConfig(os.Args[2:]))
+		}
 	}
-	defer dbpool.Close()
 
-	log.Println("Testing database connection...")
-	var version string
-	err = dbpool.QueryRow(context.Background(), "SELECT version()").Scan(&version)
+	cfg, args := loadConfig("server", os.Args[1:])
+	if len(args) > 0 {
+		fatal("Unknown command, expected migrate or config", "command", args[0])
+	}
+	slog.Info("Starting LogSentinel server")
+	if envErr != nil {
+		slog.Debug("No .env file loaded", "err", envErr)
+	}
+
+	traces := startTracing(cfg.Tracing)
+	dbpool := connectDatabase(cfg.Database)
+	prometheus.MustRegister(metrics.NewPoolCollector(dbpool))
+
+	migrator, err := db.NewMigrator(dbpool)
 	if err != nil {
-		log.Fatalf("Failed to query database: %v\n", err)
+		fatal("Invalid migrations", "err", err)
+	}
+	if cfg.Database.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			fatal("Failed to migrate database", "err", err)
+		}
+		for _, m := range applied {
+			slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+		}
+	} else if pending, err := migrator.Pending(context.Background()); err != nil {
+		slog.Error("Unable to check migrations", "err", err)
+	} else if pending > 0 {
+		slog.Warn("Pending migrations, run `server migrate up` or set database.auto_migrate", "pending", pending)
 	}
-	log.Printf("Connected to PostgreSQL: %s", version)
 
 	// teamRepository := teamrepo.NewPostgresRepository(dbpool)
 	projectRepository := projectrepo.NewPostgresRepository(dbpool)
 	userRepository := userrepo.NewPostgresRepository(dbpool)
+	retentionRepository := retentionrepo.NewPostgresRepository(dbpool)
+	jobRunRepository := jobrunrepo.NewPostgresRepository(dbpool)
+	rehydrationRepository := rehydrationrepo.NewPostgresRepository(dbpool)
+	alertRepository := alertrepo.NewPostgresRepository(dbpool)
+	logStatsRepository := logstatsrepo.NewPostgresRepository(dbpool)
+	patternRepository := patternrepo.NewPostgresRepository(dbpool)
+	anomalyRepository := anomalyrepo.NewPostgresRepository(dbpool)
+	redactionRepository := redactionrepo.NewPostgresRepository(dbpool)
+	processingRepository := processingrepo.NewPostgresRepository(dbpool)
+	categoryRepository := categoryrepo.NewPostgresRepository(dbpool)
+	clientIdentityRepository := clientidentityrepo.NewPostgresRepository(dbpool)
+
+	projectOwners := authz.NewProjectOwners(projectRepository)
+
+	jobScheduler := scheduler.New(dbpool, jobRunRepository)
+
+	categories := category.NewRegistry(categoryRepository)
+	if err := categories.Start(context.Background()); err != nil {
+		fatal("Unable to load categories", "err", err)
+	}
 
 	// teamSvc := teamservice.NewTeamService(teamRepository)
-	projectSvc := projectservice.NewProjectService(projectRepository, userRepository)
-	userSvc := userservice.NewUserService(userRepository)
-	logSvc := logservice.NewLogService(dbpool)
-	cronSvc := cronservice.NewCronService(dbpool)
-	cronSvc.Start()
-
-	log.Printf("Initializing gRPC server on port %s...", port)
-	lis, err := net.Listen("tcp", port)
+	projectSvc := projectservice.NewProjectService(projectRepository, userRepository, retentionRepository, projectOwners, categoryRepository, categories, clientIdentityRepository)
+	notifier := getNotifier(cfg.Notify)
+	rateLimiter := ratelimit.NewRateLimiter(cfg.RateLimit.FreeLogsPerMinute)
+	userSvc := userservice.NewUserService(userRepository, notifier, rateLimiter)
+
+	alertEngine := alerting.NewEngine(dbpool, alertRepository, notifier)
+	if err := alertEngine.Start(context.Background()); err != nil {
+		fatal("Unable to start alert engine", "err", err)
+	}
+	if err := alertEngine.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register alert jobs", "err", err)
+	}
+	alertSvc := alertservice.NewAlertService(alertRepository, projectOwners, anomalyRepository, alertEngine)
+
+	rollupConfig := rollup.DefaultConfig()
+	rollupConfig.MinuteRetention = cfg.Retention.RollupMinutes
+	rollupConfig.HourRetention = cfg.Retention.RollupHours
+	rollups := rollup.NewAggregator(logStatsRepository, rollupConfig)
+	if err := rollups.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register rollup jobs", "err", err)
+	}
+	rollups.Start()
+
+	anomalyConfig := anomaly.DefaultConfig()
+	anomalyConfig.Threshold = cfg.Anomaly.Threshold
+	anomalyConfig.MinCount = cfg.Anomaly.MinCount
+	if baseline := time.Duration(anomalyConfig.Weeks) * 7 * 24 * time.Hour; rollupConfig.HourRetention < baseline {
+		slog.Warn("retention.rollup_hours is shorter than the anomaly baseline, baselines will be incomplete", "baseline", baseline)
+	}
+	detector := anomaly.NewDetector(logStatsRepository, anomalyRepository, notifier, anomalyConfig)
+	if err := detector.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register anomaly jobs", "err", err)
+	}
+
+	patternConfig := clustering.DefaultConfig()
+	patternConfig.Retention = cfg.Retention.Patterns
+	patterns := clustering.NewClusterer(patternRepository, patternConfig)
+	if err := patterns.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register pattern jobs", "err", err)
+	}
+	patterns.Start()
+
+	redactor, err := redact.NewRedactor(redactionRepository, getRedactConfig(cfg.Redact))
+	if err != nil {
+		fatal("Invalid redaction config", "err", err)
+	}
+	if err := redactor.Start(context.Background()); err != nil {
+		fatal("Unable to start redactor", "err", err)
+	}
+	redactionSvc := redactionservice.NewRedactionService(redactionRepository, projectOwners, redactor)
+
+	processor := pipeline.NewEngine(processingRepository, categories)
+	if err := processor.Start(context.Background()); err != nil {
+		fatal("Unable to start processing rules", "err", err)
+	}
+	processingSvc := processingservice.NewProcessingService(processingRepository, projectOwners, processor, redactor, categories)
+
+	logSvc := logservice.NewLogService(dbpool, rateLimiter, alertEngine, notifier, rollups, logStatsRepository, patterns, patternRepository, redactor, processor, categories)
+	archiver := getArchiver(cfg.Archive)
+
+	rehydrateConfig := rehydrate.DefaultConfig()
+	rehydrateConfig.DefaultTTL = cfg.Rehydration.DefaultTTL
+	rehydrateConfig.MaxTTL = cfg.Rehydration.MaxTTL
+	rehydrator := rehydrate.NewRehydrator(dbpool, archiver, rehydrationRepository, rehydrateConfig)
+	if err := rehydrator.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register rehydration jobs", "err", err)
+	}
+
+	adminSvc := adminservice.NewAdminService(jobScheduler, rehydrator, notifier, logStatsRepository, cfg.Server.AdminToken)
+
+	cronSvc := cronservice.NewCronService(dbpool, retentionRepository, jobRunRepository, archiver, getCronConfig(cfg.Retention))
+	if err := cronSvc.RegisterJobs(jobScheduler); err != nil {
+		fatal("Unable to register cron jobs", "err", err)
+	}
+	jobScheduler.Start()
+
+	lis, err := net.Listen("tcp", cfg.Server.ListenAddress)
 	if err != nil {
-		log.Fatalf("unable to create connection pool: %v", err)
+		fatal("Unable to listen", "address", cfg.Server.ListenAddress, "err", err)
 	}
 
-	s := grpc.NewServer()
+	var serverOpts []grpc.ServerOption
+	var certReloader *certs.Reloader
+	if cfg.TLS.Enabled() {
+		certReloader = certs.NewReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile, cfg.TLS.RequireClientCert)
+		if err := certReloader.Start(context.Background()); err != nil {
+			fatal("Unable to load TLS certificates", "err", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certReloader.TLSConfig())))
+		slog.Info("TLS enabled on the gRPC listener")
+		if certReloader.VerifiesClients() {
+			slog.Info("Client certificates are verified, mapped identities can replace API keys")
+		}
+	}
+	serverOpts = append(serverOpts,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(), metrics.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(), metrics.StreamServerInterceptor()),
+	)
+	s := grpc.NewServer(serverOpts...)
 
 	pb.RegisterLogServiceServer(s, logSvc)
 	// pb.RegisterTeamServiceServer(s, teamSvc)
 	pb.RegisterProjectServiceServer(s, projectSvc)
 	pb.RegisterUserServiceServer(s, userSvc)
+	pb.RegisterAdminServiceServer(s, adminSvc)
+	pb.RegisterAlertServiceServer(s, alertSvc)
+	pb.RegisterRedactionServiceServer(s, redactionSvc)
+	pb.RegisterProcessingServiceServer(s, processingSvc)
 
-    if os.Getenv("ENABLE_GRPC_REFLECTION") == "true" {
-        reflection.Register(s)
-        log.Println("gRPC reflection enabled")
-    }
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	checker := health.NewChecker(healthServer)
+	checker.AddCheck("database", health.DatabaseCheck(dbpool))
+	checker.AddCheck("migrations", health.MigrationCheck(migrator))
+	checker.AddInfo("backlog", health.BacklogCheck(map[string]health.Queue{
+		"alert":        alertEngine,
+		"notification": notifier,
+	}))
+	for _, service := range []string{
+		pb.LogService_ServiceDesc.ServiceName,
+		pb.ProjectService_ServiceDesc.ServiceName,
+		pb.UserService_ServiceDesc.ServiceName,
+		pb.AdminService_ServiceDesc.ServiceName,
+		pb.AlertService_ServiceDesc.ServiceName,
+		pb.RedactionService_ServiceDesc.ServiceName,
+		pb.ProcessingService_ServiceDesc.ServiceName,
+	} {
+		checker.AddService(service, "database", "migrations")
+	}
+	checker.Start()
+
+	if cfg.Server.Reflection {
+		reflection.Register(s)
+		slog.Info("gRPC reflection enabled")
+	}
+
+	httpServer := startHTTPServer(cfg.HTTP, checker)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("gRPC server listening", "address", cfg.Server.ListenAddress)
+		serveErr <- s.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		fatal("Failed to serve gRPC", "err", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutdown signal received, draining connections")
+	// Stopped in dependency order once RPCs are drained: in-flight writes
+	// finish, background components flush and stop, and only then is the
+	// database pool they all share closed. Spans are flushed last.
+	stoppers := []stopper{
+		{"Timed out waiting for in-flight log writes", logSvc.Wait},
+		{"Error flushing log rollups", rollups.Stop},
+		{"Error flushing log patterns", patterns.Stop},
+		{"Error flushing redaction counts", redactor.Stop},
+		{"Timed out stopping processing rule reloads", processor.Stop},
+		{"Timed out stopping category reloads", categories.Stop},
+	}
+	if certReloader != nil {
+		stoppers = append(stoppers, stopper{"Timed out stopping certificate reloads", certReloader.Stop})
+	}
+	stoppers = append(stoppers,
+		stopper{"Timed out matching queued alert events", alertEngine.Stop},
+		stopper{"Timed out stopping scheduled jobs", jobScheduler.Stop},
+		stopper{"Timed out stopping log rehydrations", rehydrator.Stop},
+		stopper{"Timed out delivering queued notifications", notifier.Stop},
+		stopper{"Timed out stopping readiness checks", checker.Stop},
+	)
+	if httpServer != nil {
+		stoppers = append(stoppers, stopper{"Error stopping HTTP server", httpServer.Shutdown})
+	}
+	stoppers = append(stoppers,
+		stopper{"Error closing database pool", func(context.Context) error {
+			dbpool.Close()
+			return nil
+		}},
+		stopper{"Error flushing traces", traces.Stop},
+	)
+	shutdown(s, checker, logSvc, stoppers, cfg.Server.ShutdownTimeout)
+	slog.Info("Server stopped")
+}
+
+// startHTTPServer serves the metrics and the liveness and readiness
+// endpoints over HTTP, it returns nil when no listen address is configured.
+func startHTTPServer(cfg config.HTTPConfig, checker *health.Checker) *http.Server {
+	if cfg.ListenAddress == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/healthz", checker.LiveHandler())
+	mux.Handle("/readyz", checker.ReadyHandler())
+
+	server := &http.Server{
+		Addr:              cfg.ListenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		slog.Info("HTTP server listening", "address", cfg.ListenAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to serve HTTP", "err", err)
+		}
+	}()
+	return server
+}
+
+// stopper is one step of the shutdown, err is logged with msg when the step
+// fails or runs out of time.
+type stopper struct {
+	msg  string
+	stop func(ctx context.Context) error
+}
+
+// shutdown reports unready, stops accepting RPCs and drains streams, then
+// runs the stoppers in order. All of it shares one deadline.
+func shutdown(s *grpc.Server, checker *health.Checker, logSvc *logservice.LogService, stoppers []stopper, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	checker.Shutdown()
+	logSvc.Drain()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("All RPCs finished")
+	case <-ctx.Done():
+		slog.Warn("Shutdown deadline reached, closing remaining RPCs")
+		s.Stop()
+	}
 
-	log.Printf("Server listening on port %s\n", port)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	for _, st := range stoppers {
+		if err := st.stop(ctx); err != nil {
+			slog.Error(st.msg, "err", err)
+		}
 	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/AjayShukla007/logsentinel/internal/db"
+)
+
+const migrateUsage = `usage: server migrate [flags] [command]
+
+commands:
+  up         apply every pending migration (default)
+  down [n]   revert the last n applied migrations, 1 by default
+  status     list the migrations and when they were applied`
+
+// runMigrate runs the migrate subcommand and returns the exit code.
+func runMigrate(args []string) int {
+	cfg, args := loadConfig("migrate", args)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	steps := 1
+	switch {
+	case command == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			fmt.Println(migrateUsage)
+			return 2
+		}
+		steps = n
+	case command == "up" || command == "status" || command == "down":
+		if len(args) > 1 {
+			fmt.Println(migrateUsage)
+			return 2
+		}
+	default:
+		fmt.Println(migrateUsage)
+		return 2
+	}
+
+	dbpool := connectDatabase(cfg.Database)
+	defer dbpool.Close()
+
+	migrator, err := db.NewMigrator(dbpool)
+	if err != nil {
+		slog.Error("Invalid migrations", "err", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			slog.Error("Migration failed", "err", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			slog.Info("Database is up to date")
+		}
+
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			slog.Info("Reverted migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			slog.Error("Migration failed", "err", err)
+			return 1
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("Unable to load migration status", "err", err)
+			return 1
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-30s  %s\n", status.Version, status.Name, applied)
+		}
+	}
+	return 0
+}
@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LogService_StreamLogs_FullMethodName    = "/logsentinel.LogService/StreamLogs"
-	LogService_SendLog_FullMethodName       = "/logsentinel.LogService/SendLog"
-	LogService_Test_FullMethodName          = "/logsentinel.LogService/Test"
-	LogService_BatchSendLogs_FullMethodName = "/logsentinel.LogService/BatchSendLogs"
-	LogService_ConnectClient_FullMethodName = "/logsentinel.LogService/ConnectClient"
+	LogService_StreamLogs_FullMethodName     = "/logsentinel.LogService/StreamLogs"
+	LogService_SendLog_FullMethodName        = "/logsentinel.LogService/SendLog"
+	LogService_Test_FullMethodName           = "/logsentinel.LogService/Test"
+	LogService_BatchSendLogs_FullMethodName  = "/logsentinel.LogService/BatchSendLogs"
+	LogService_ConnectClient_FullMethodName  = "/logsentinel.LogService/ConnectClient"
+	LogService_ExportLogs_FullMethodName     = "/logsentinel.LogService/ExportLogs"
+	LogService_GetLogStats_FullMethodName    = "/logsentinel.LogService/GetLogStats"
+	LogService_GetTopPatterns_FullMethodName = "/logsentinel.LogService/GetTopPatterns"
 )
 
 // LogServiceClient is the client API for LogService service.
@@ -35,6 +38,9 @@ type LogServiceClient interface {
 	Test(ctx context.Context, in *TestRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TestResponse], error)
 	BatchSendLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[LogRequest, BatchLogResponse], error)
 	ConnectClient(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientMessage, ServerMessage], error)
+	ExportLogs(ctx context.Context, in *ExportLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	GetLogStats(ctx context.Context, in *GetLogStatsRequest, opts ...grpc.CallOption) (*LogStats, error)
+	GetTopPatterns(ctx context.Context, in *GetTopPatternsRequest, opts ...grpc.CallOption) (*TopPatterns, error)
 }
 
 type logServiceClient struct {
@@ -119,6 +125,45 @@ func (c *logServiceClient) ConnectClient(ctx context.Context, opts ...grpc.CallO
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LogService_ConnectClientClient = grpc.BidiStreamingClient[ClientMessage, ServerMessage]
 
+func (c *logServiceClient) ExportLogs(ctx context.Context, in *ExportLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogService_ServiceDesc.Streams[4], LogService_ExportLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportLogsRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogService_ExportLogsClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *logServiceClient) GetLogStats(ctx context.Context, in *GetLogStatsRequest, opts ...grpc.CallOption) (*LogStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogStats)
+	err := c.cc.Invoke(ctx, LogService_GetLogStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logServiceClient) GetTopPatterns(ctx context.Context, in *GetTopPatternsRequest, opts ...grpc.CallOption) (*TopPatterns, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopPatterns)
+	err := c.cc.Invoke(ctx, LogService_GetTopPatterns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogServiceServer is the server API for LogService service.
 // All implementations must embed UnimplementedLogServiceServer
 // for forward compatibility.
@@ -128,6 +173,9 @@ type LogServiceServer interface {
 	Test(*TestRequest, grpc.ServerStreamingServer[TestResponse]) error
 	BatchSendLogs(grpc.ClientStreamingServer[LogRequest, BatchLogResponse]) error
 	ConnectClient(grpc.BidiStreamingServer[ClientMessage, ServerMessage]) error
+	ExportLogs(*ExportLogsRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	GetLogStats(context.Context, *GetLogStatsRequest) (*LogStats, error)
+	GetTopPatterns(context.Context, *GetTopPatternsRequest) (*TopPatterns, error)
 	mustEmbedUnimplementedLogServiceServer()
 }
 
@@ -153,6 +201,15 @@ func (UnimplementedLogServiceServer) BatchSendLogs(grpc.ClientStreamingServer[Lo
 func (UnimplementedLogServiceServer) ConnectClient(grpc.BidiStreamingServer[ClientMessage, ServerMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method ConnectClient not implemented")
 }
+func (UnimplementedLogServiceServer) ExportLogs(*ExportLogsRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportLogs not implemented")
+}
+func (UnimplementedLogServiceServer) GetLogStats(context.Context, *GetLogStatsRequest) (*LogStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogStats not implemented")
+}
+func (UnimplementedLogServiceServer) GetTopPatterns(context.Context, *GetTopPatternsRequest) (*TopPatterns, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopPatterns not implemented")
+}
 func (UnimplementedLogServiceServer) mustEmbedUnimplementedLogServiceServer() {}
 func (UnimplementedLogServiceServer) testEmbeddedByValue()                    {}
 
@@ -228,6 +285,53 @@ func _LogService_ConnectClient_Handler(srv interface{}, stream grpc.ServerStream
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LogService_ConnectClientServer = grpc.BidiStreamingServer[ClientMessage, ServerMessage]
 
+func _LogService_ExportLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogServiceServer).ExportLogs(m, &grpc.GenericServerStream[ExportLogsRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogService_ExportLogsServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _LogService_GetLogStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).GetLogStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogService_GetLogStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).GetLogStats(ctx, req.(*GetLogStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogService_GetTopPatterns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopPatternsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogServiceServer).GetTopPatterns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogService_GetTopPatterns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogServiceServer).GetTopPatterns(ctx, req.(*GetTopPatternsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LogService_ServiceDesc is the grpc.ServiceDesc for LogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -239,6 +343,14 @@ var LogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendLog",
 			Handler:    _LogService_SendLog_Handler,
 		},
+		{
+			MethodName: "GetLogStats",
+			Handler:    _LogService_GetLogStats_Handler,
+		},
+		{
+			MethodName: "GetTopPatterns",
+			Handler:    _LogService_GetTopPatterns_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -262,6 +374,11 @@ var LogService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ExportLogs",
+			Handler:       _LogService_ExportLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/logsentinel.proto",
 }
@@ -521,9 +638,15 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	ProjectService_CreateProject_FullMethodName = "/logsentinel.ProjectService/CreateProject"
-	ProjectService_GetProject_FullMethodName    = "/logsentinel.ProjectService/GetProject"
-	ProjectService_DeleteProject_FullMethodName = "/logsentinel.ProjectService/DeleteProject"
+	ProjectService_CreateProject_FullMethodName        = "/logsentinel.ProjectService/CreateProject"
+	ProjectService_GetProject_FullMethodName           = "/logsentinel.ProjectService/GetProject"
+	ProjectService_DeleteProject_FullMethodName        = "/logsentinel.ProjectService/DeleteProject"
+	ProjectService_GetRetentionPolicy_FullMethodName   = "/logsentinel.ProjectService/GetRetentionPolicy"
+	ProjectService_SetRetentionPolicy_FullMethodName   = "/logsentinel.ProjectService/SetRetentionPolicy"
+	ProjectService_GetProjectCategories_FullMethodName = "/logsentinel.ProjectService/GetProjectCategories"
+	ProjectService_SetProjectCategories_FullMethodName = "/logsentinel.ProjectService/SetProjectCategories"
+	ProjectService_GetClientIdentities_FullMethodName  = "/logsentinel.ProjectService/GetClientIdentities"
+	ProjectService_SetClientIdentities_FullMethodName  = "/logsentinel.ProjectService/SetClientIdentities"
 )
 
 // ProjectServiceClient is the client API for ProjectService service.
@@ -533,6 +656,12 @@ type ProjectServiceClient interface {
 	CreateProject(ctx context.Context, in *CreateProjectRequest, opts ...grpc.CallOption) (*Project, error)
 	GetProject(ctx context.Context, in *GetProjectRequest, opts ...grpc.CallOption) (*Project, error)
 	DeleteProject(ctx context.Context, in *DeleteProjectRequest, opts ...grpc.CallOption) (*DeleteProjectResponse, error)
+	GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	GetProjectCategories(ctx context.Context, in *GetProjectCategoriesRequest, opts ...grpc.CallOption) (*ProjectCategories, error)
+	SetProjectCategories(ctx context.Context, in *SetProjectCategoriesRequest, opts ...grpc.CallOption) (*ProjectCategories, error)
+	GetClientIdentities(ctx context.Context, in *GetClientIdentitiesRequest, opts ...grpc.CallOption) (*ClientIdentities, error)
+	SetClientIdentities(ctx context.Context, in *SetClientIdentitiesRequest, opts ...grpc.CallOption) (*ClientIdentities, error)
 }
 
 type projectServiceClient struct {
@@ -573,6 +702,66 @@ func (c *projectServiceClient) DeleteProject(ctx context.Context, in *DeleteProj
 	return out, nil
 }
 
+func (c *projectServiceClient) GetRetentionPolicy(ctx context.Context, in *GetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, ProjectService_GetRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) SetRetentionPolicy(ctx context.Context, in *SetRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, ProjectService_SetRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) GetProjectCategories(ctx context.Context, in *GetProjectCategoriesRequest, opts ...grpc.CallOption) (*ProjectCategories, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProjectCategories)
+	err := c.cc.Invoke(ctx, ProjectService_GetProjectCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) SetProjectCategories(ctx context.Context, in *SetProjectCategoriesRequest, opts ...grpc.CallOption) (*ProjectCategories, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProjectCategories)
+	err := c.cc.Invoke(ctx, ProjectService_SetProjectCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) GetClientIdentities(ctx context.Context, in *GetClientIdentitiesRequest, opts ...grpc.CallOption) (*ClientIdentities, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientIdentities)
+	err := c.cc.Invoke(ctx, ProjectService_GetClientIdentities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) SetClientIdentities(ctx context.Context, in *SetClientIdentitiesRequest, opts ...grpc.CallOption) (*ClientIdentities, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClientIdentities)
+	err := c.cc.Invoke(ctx, ProjectService_SetClientIdentities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProjectServiceServer is the server API for ProjectService service.
 // All implementations must embed UnimplementedProjectServiceServer
 // for forward compatibility.
@@ -580,6 +769,12 @@ type ProjectServiceServer interface {
 	CreateProject(context.Context, *CreateProjectRequest) (*Project, error)
 	GetProject(context.Context, *GetProjectRequest) (*Project, error)
 	DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error)
+	GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error)
+	SetRetentionPolicy(context.Context, *SetRetentionPolicyRequest) (*RetentionPolicy, error)
+	GetProjectCategories(context.Context, *GetProjectCategoriesRequest) (*ProjectCategories, error)
+	SetProjectCategories(context.Context, *SetProjectCategoriesRequest) (*ProjectCategories, error)
+	GetClientIdentities(context.Context, *GetClientIdentitiesRequest) (*ClientIdentities, error)
+	SetClientIdentities(context.Context, *SetClientIdentitiesRequest) (*ClientIdentities, error)
 	mustEmbedUnimplementedProjectServiceServer()
 }
 
@@ -599,6 +794,24 @@ func (UnimplementedProjectServiceServer) GetProject(context.Context, *GetProject
 func (UnimplementedProjectServiceServer) DeleteProject(context.Context, *DeleteProjectRequest) (*DeleteProjectResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
 }
+func (UnimplementedProjectServiceServer) GetRetentionPolicy(context.Context, *GetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionPolicy not implemented")
+}
+func (UnimplementedProjectServiceServer) SetRetentionPolicy(context.Context, *SetRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRetentionPolicy not implemented")
+}
+func (UnimplementedProjectServiceServer) GetProjectCategories(context.Context, *GetProjectCategoriesRequest) (*ProjectCategories, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectCategories not implemented")
+}
+func (UnimplementedProjectServiceServer) SetProjectCategories(context.Context, *SetProjectCategoriesRequest) (*ProjectCategories, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProjectCategories not implemented")
+}
+func (UnimplementedProjectServiceServer) GetClientIdentities(context.Context, *GetClientIdentitiesRequest) (*ClientIdentities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClientIdentities not implemented")
+}
+func (UnimplementedProjectServiceServer) SetClientIdentities(context.Context, *SetClientIdentitiesRequest) (*ClientIdentities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetClientIdentities not implemented")
+}
 func (UnimplementedProjectServiceServer) mustEmbedUnimplementedProjectServiceServer() {}
 func (UnimplementedProjectServiceServer) testEmbeddedByValue()                        {}
 
@@ -674,6 +887,114 @@ func _ProjectService_DeleteProject_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProjectService_GetRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).GetRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_GetRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).GetRetentionPolicy(ctx, req.(*GetRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_SetRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).SetRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_SetRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).SetRetentionPolicy(ctx, req.(*SetRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_GetProjectCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).GetProjectCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_GetProjectCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).GetProjectCategories(ctx, req.(*GetProjectCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_SetProjectCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProjectCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).SetProjectCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_SetProjectCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).SetProjectCategories(ctx, req.(*SetProjectCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_GetClientIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClientIdentitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).GetClientIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_GetClientIdentities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).GetClientIdentities(ctx, req.(*GetClientIdentitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_SetClientIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClientIdentitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).SetClientIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_SetClientIdentities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).SetClientIdentities(ctx, req.(*SetClientIdentitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProjectService_ServiceDesc is the grpc.ServiceDesc for ProjectService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -693,6 +1014,1126 @@ var ProjectService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProject",
 			Handler:    _ProjectService_DeleteProject_Handler,
 		},
+		{
+			MethodName: "GetRetentionPolicy",
+			Handler:    _ProjectService_GetRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "SetRetentionPolicy",
+			Handler:    _ProjectService_SetRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "GetProjectCategories",
+			Handler:    _ProjectService_GetProjectCategories_Handler,
+		},
+		{
+			MethodName: "SetProjectCategories",
+			Handler:    _ProjectService_SetProjectCategories_Handler,
+		},
+		{
+			MethodName: "GetClientIdentities",
+			Handler:    _ProjectService_GetClientIdentities_Handler,
+		},
+		{
+			MethodName: "SetClientIdentities",
+			Handler:    _ProjectService_SetClientIdentities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/logsentinel.proto",
+}
+
+const (
+	AdminService_ListJobs_FullMethodName         = "/logsentinel.AdminService/ListJobs"
+	AdminService_TriggerJob_FullMethodName       = "/logsentinel.AdminService/TriggerJob"
+	AdminService_RehydrateLogs_FullMethodName    = "/logsentinel.AdminService/RehydrateLogs"
+	AdminService_ListRehydrations_FullMethodName = "/logsentinel.AdminService/ListRehydrations"
+	AdminService_TestNotification_FullMethodName = "/logsentinel.AdminService/TestNotification"
+	AdminService_ExportUsage_FullMethodName      = "/logsentinel.AdminService/ExportUsage"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...grpc.CallOption) (*TriggerJobResponse, error)
+	// RehydrateLogs returns the rehydration while it is still restoring, poll
+	// ListRehydrations until its status is ready or failed
+	RehydrateLogs(ctx context.Context, in *RehydrateLogsRequest, opts ...grpc.CallOption) (*Rehydration, error)
+	ListRehydrations(ctx context.Context, in *ListRehydrationsRequest, opts ...grpc.CallOption) (*ListRehydrationsResponse, error)
+	TestNotification(ctx context.Context, in *TestNotificationRequest, opts ...grpc.CallOption) (*TestNotificationResponse, error)
+	ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*UsageReport, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...grpc.CallOption) (*TriggerJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerJobResponse)
+	err := c.cc.Invoke(ctx, AdminService_TriggerJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RehydrateLogs(ctx context.Context, in *RehydrateLogsRequest, opts ...grpc.CallOption) (*Rehydration, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rehydration)
+	err := c.cc.Invoke(ctx, AdminService_RehydrateLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRehydrations(ctx context.Context, in *ListRehydrationsRequest, opts ...grpc.CallOption) (*ListRehydrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRehydrationsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListRehydrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) TestNotification(ctx context.Context, in *TestNotificationRequest, opts ...grpc.CallOption) (*TestNotificationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestNotificationResponse)
+	err := c.cc.Invoke(ctx, AdminService_TestNotification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportUsage(ctx context.Context, in *ExportUsageRequest, opts ...grpc.CallOption) (*UsageReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsageReport)
+	err := c.cc.Invoke(ctx, AdminService_ExportUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+type AdminServiceServer interface {
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	TriggerJob(context.Context, *TriggerJobRequest) (*TriggerJobResponse, error)
+	// RehydrateLogs returns the rehydration while it is still restoring, poll
+	// ListRehydrations until its status is ready or failed
+	RehydrateLogs(context.Context, *RehydrateLogsRequest) (*Rehydration, error)
+	ListRehydrations(context.Context, *ListRehydrationsRequest) (*ListRehydrationsResponse, error)
+	TestNotification(context.Context, *TestNotificationRequest) (*TestNotificationResponse, error)
+	ExportUsage(context.Context, *ExportUsageRequest) (*UsageReport, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedAdminServiceServer) TriggerJob(context.Context, *TriggerJobRequest) (*TriggerJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerJob not implemented")
+}
+func (UnimplementedAdminServiceServer) RehydrateLogs(context.Context, *RehydrateLogsRequest) (*Rehydration, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RehydrateLogs not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRehydrations(context.Context, *ListRehydrationsRequest) (*ListRehydrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRehydrations not implemented")
+}
+func (UnimplementedAdminServiceServer) TestNotification(context.Context, *TestNotificationRequest) (*TestNotificationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestNotification not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportUsage(context.Context, *ExportUsageRequest) (*UsageReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUsage not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_TriggerJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).TriggerJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_TriggerJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).TriggerJob(ctx, req.(*TriggerJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RehydrateLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RehydrateLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RehydrateLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RehydrateLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RehydrateLogs(ctx, req.(*RehydrateLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRehydrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRehydrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRehydrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRehydrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRehydrations(ctx, req.(*ListRehydrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_TestNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).TestNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_TestNotification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).TestNotification(ctx, req.(*TestNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportUsage(ctx, req.(*ExportUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logsentinel.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListJobs",
+			Handler:    _AdminService_ListJobs_Handler,
+		},
+		{
+			MethodName: "TriggerJob",
+			Handler:    _AdminService_TriggerJob_Handler,
+		},
+		{
+			MethodName: "RehydrateLogs",
+			Handler:    _AdminService_RehydrateLogs_Handler,
+		},
+		{
+			MethodName: "ListRehydrations",
+			Handler:    _AdminService_ListRehydrations_Handler,
+		},
+		{
+			MethodName: "TestNotification",
+			Handler:    _AdminService_TestNotification_Handler,
+		},
+		{
+			MethodName: "ExportUsage",
+			Handler:    _AdminService_ExportUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/logsentinel.proto",
+}
+
+const (
+	AlertService_CreateAlertRule_FullMethodName = "/logsentinel.AlertService/CreateAlertRule"
+	AlertService_UpdateAlertRule_FullMethodName = "/logsentinel.AlertService/UpdateAlertRule"
+	AlertService_DeleteAlertRule_FullMethodName = "/logsentinel.AlertService/DeleteAlertRule"
+	AlertService_ListAlertRules_FullMethodName  = "/logsentinel.AlertService/ListAlertRules"
+	AlertService_ListAlerts_FullMethodName      = "/logsentinel.AlertService/ListAlerts"
+	AlertService_ListAnomalies_FullMethodName   = "/logsentinel.AlertService/ListAnomalies"
+)
+
+// AlertServiceClient is the client API for AlertService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AlertServiceClient interface {
+	CreateAlertRule(ctx context.Context, in *SaveAlertRuleRequest, opts ...grpc.CallOption) (*AlertRule, error)
+	UpdateAlertRule(ctx context.Context, in *SaveAlertRuleRequest, opts ...grpc.CallOption) (*AlertRule, error)
+	DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error)
+	ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error)
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error)
+}
+
+type alertServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlertServiceClient(cc grpc.ClientConnInterface) AlertServiceClient {
+	return &alertServiceClient{cc}
+}
+
+func (c *alertServiceClient) CreateAlertRule(ctx context.Context, in *SaveAlertRuleRequest, opts ...grpc.CallOption) (*AlertRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRule)
+	err := c.cc.Invoke(ctx, AlertService_CreateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) UpdateAlertRule(ctx context.Context, in *SaveAlertRuleRequest, opts ...grpc.CallOption) (*AlertRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRule)
+	err := c.cc.Invoke(ctx, AlertService_UpdateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlertRuleResponse)
+	err := c.cc.Invoke(ctx, AlertService_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertRulesResponse)
+	err := c.cc.Invoke(ctx, AlertService_ListAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, AlertService_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) ListAnomalies(ctx context.Context, in *ListAnomaliesRequest, opts ...grpc.CallOption) (*ListAnomaliesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAnomaliesResponse)
+	err := c.cc.Invoke(ctx, AlertService_ListAnomalies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AlertServiceServer is the server API for AlertService service.
+// All implementations must embed UnimplementedAlertServiceServer
+// for forward compatibility.
+type AlertServiceServer interface {
+	CreateAlertRule(context.Context, *SaveAlertRuleRequest) (*AlertRule, error)
+	UpdateAlertRule(context.Context, *SaveAlertRuleRequest) (*AlertRule, error)
+	DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error)
+	ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error)
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error)
+	mustEmbedUnimplementedAlertServiceServer()
+}
+
+// UnimplementedAlertServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAlertServiceServer struct{}
+
+func (UnimplementedAlertServiceServer) CreateAlertRule(context.Context, *SaveAlertRuleRequest) (*AlertRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) UpdateAlertRule(context.Context, *SaveAlertRuleRequest) (*AlertRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertRules not implemented")
+}
+func (UnimplementedAlertServiceServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedAlertServiceServer) ListAnomalies(context.Context, *ListAnomaliesRequest) (*ListAnomaliesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAnomalies not implemented")
+}
+func (UnimplementedAlertServiceServer) mustEmbedUnimplementedAlertServiceServer() {}
+func (UnimplementedAlertServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAlertServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlertServiceServer will
+// result in compilation errors.
+type UnsafeAlertServiceServer interface {
+	mustEmbedUnimplementedAlertServiceServer()
+}
+
+func RegisterAlertServiceServer(s grpc.ServiceRegistrar, srv AlertServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAlertServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AlertService_ServiceDesc, srv)
+}
+
+func _AlertService_CreateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).CreateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_CreateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).CreateAlertRule(ctx, req.(*SaveAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_UpdateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).UpdateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_UpdateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).UpdateAlertRule(ctx, req.(*SaveAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).DeleteAlertRule(ctx, req.(*DeleteAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_ListAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).ListAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_ListAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).ListAlertRules(ctx, req.(*ListAlertRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_ListAnomalies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAnomaliesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).ListAnomalies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_ListAnomalies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).ListAnomalies(ctx, req.(*ListAnomaliesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AlertService_ServiceDesc is the grpc.ServiceDesc for AlertService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AlertService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logsentinel.AlertService",
+	HandlerType: (*AlertServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAlertRule",
+			Handler:    _AlertService_CreateAlertRule_Handler,
+		},
+		{
+			MethodName: "UpdateAlertRule",
+			Handler:    _AlertService_UpdateAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _AlertService_DeleteAlertRule_Handler,
+		},
+		{
+			MethodName: "ListAlertRules",
+			Handler:    _AlertService_ListAlertRules_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _AlertService_ListAlerts_Handler,
+		},
+		{
+			MethodName: "ListAnomalies",
+			Handler:    _AlertService_ListAnomalies_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/logsentinel.proto",
+}
+
+const (
+	RedactionService_SaveRedactionRule_FullMethodName   = "/logsentinel.RedactionService/SaveRedactionRule"
+	RedactionService_DeleteRedactionRule_FullMethodName = "/logsentinel.RedactionService/DeleteRedactionRule"
+	RedactionService_ListRedactionRules_FullMethodName  = "/logsentinel.RedactionService/ListRedactionRules"
+	RedactionService_GetRedactionStats_FullMethodName   = "/logsentinel.RedactionService/GetRedactionStats"
+)
+
+// RedactionServiceClient is the client API for RedactionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RedactionServiceClient interface {
+	SaveRedactionRule(ctx context.Context, in *SaveRedactionRuleRequest, opts ...grpc.CallOption) (*RedactionRule, error)
+	DeleteRedactionRule(ctx context.Context, in *DeleteRedactionRuleRequest, opts ...grpc.CallOption) (*DeleteRedactionRuleResponse, error)
+	ListRedactionRules(ctx context.Context, in *ListRedactionRulesRequest, opts ...grpc.CallOption) (*ListRedactionRulesResponse, error)
+	GetRedactionStats(ctx context.Context, in *GetRedactionStatsRequest, opts ...grpc.CallOption) (*RedactionStats, error)
+}
+
+type redactionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRedactionServiceClient(cc grpc.ClientConnInterface) RedactionServiceClient {
+	return &redactionServiceClient{cc}
+}
+
+func (c *redactionServiceClient) SaveRedactionRule(ctx context.Context, in *SaveRedactionRuleRequest, opts ...grpc.CallOption) (*RedactionRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedactionRule)
+	err := c.cc.Invoke(ctx, RedactionService_SaveRedactionRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redactionServiceClient) DeleteRedactionRule(ctx context.Context, in *DeleteRedactionRuleRequest, opts ...grpc.CallOption) (*DeleteRedactionRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRedactionRuleResponse)
+	err := c.cc.Invoke(ctx, RedactionService_DeleteRedactionRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redactionServiceClient) ListRedactionRules(ctx context.Context, in *ListRedactionRulesRequest, opts ...grpc.CallOption) (*ListRedactionRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRedactionRulesResponse)
+	err := c.cc.Invoke(ctx, RedactionService_ListRedactionRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *redactionServiceClient) GetRedactionStats(ctx context.Context, in *GetRedactionStatsRequest, opts ...grpc.CallOption) (*RedactionStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedactionStats)
+	err := c.cc.Invoke(ctx, RedactionService_GetRedactionStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedactionServiceServer is the server API for RedactionService service.
+// All implementations must embed UnimplementedRedactionServiceServer
+// for forward compatibility.
+type RedactionServiceServer interface {
+	SaveRedactionRule(context.Context, *SaveRedactionRuleRequest) (*RedactionRule, error)
+	DeleteRedactionRule(context.Context, *DeleteRedactionRuleRequest) (*DeleteRedactionRuleResponse, error)
+	ListRedactionRules(context.Context, *ListRedactionRulesRequest) (*ListRedactionRulesResponse, error)
+	GetRedactionStats(context.Context, *GetRedactionStatsRequest) (*RedactionStats, error)
+	mustEmbedUnimplementedRedactionServiceServer()
+}
+
+// UnimplementedRedactionServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRedactionServiceServer struct{}
+
+func (UnimplementedRedactionServiceServer) SaveRedactionRule(context.Context, *SaveRedactionRuleRequest) (*RedactionRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveRedactionRule not implemented")
+}
+func (UnimplementedRedactionServiceServer) DeleteRedactionRule(context.Context, *DeleteRedactionRuleRequest) (*DeleteRedactionRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRedactionRule not implemented")
+}
+func (UnimplementedRedactionServiceServer) ListRedactionRules(context.Context, *ListRedactionRulesRequest) (*ListRedactionRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRedactionRules not implemented")
+}
+func (UnimplementedRedactionServiceServer) GetRedactionStats(context.Context, *GetRedactionStatsRequest) (*RedactionStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRedactionStats not implemented")
+}
+func (UnimplementedRedactionServiceServer) mustEmbedUnimplementedRedactionServiceServer() {}
+func (UnimplementedRedactionServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeRedactionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RedactionServiceServer will
+// result in compilation errors.
+type UnsafeRedactionServiceServer interface {
+	mustEmbedUnimplementedRedactionServiceServer()
+}
+
+func RegisterRedactionServiceServer(s grpc.ServiceRegistrar, srv RedactionServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRedactionServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RedactionService_ServiceDesc, srv)
+}
+
+func _RedactionService_SaveRedactionRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveRedactionRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedactionServiceServer).SaveRedactionRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RedactionService_SaveRedactionRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedactionServiceServer).SaveRedactionRule(ctx, req.(*SaveRedactionRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedactionService_DeleteRedactionRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRedactionRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedactionServiceServer).DeleteRedactionRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RedactionService_DeleteRedactionRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedactionServiceServer).DeleteRedactionRule(ctx, req.(*DeleteRedactionRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedactionService_ListRedactionRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRedactionRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedactionServiceServer).ListRedactionRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RedactionService_ListRedactionRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedactionServiceServer).ListRedactionRules(ctx, req.(*ListRedactionRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RedactionService_GetRedactionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRedactionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RedactionServiceServer).GetRedactionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RedactionService_GetRedactionStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RedactionServiceServer).GetRedactionStats(ctx, req.(*GetRedactionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RedactionService_ServiceDesc is the grpc.ServiceDesc for RedactionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RedactionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logsentinel.RedactionService",
+	HandlerType: (*RedactionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SaveRedactionRule",
+			Handler:    _RedactionService_SaveRedactionRule_Handler,
+		},
+		{
+			MethodName: "DeleteRedactionRule",
+			Handler:    _RedactionService_DeleteRedactionRule_Handler,
+		},
+		{
+			MethodName: "ListRedactionRules",
+			Handler:    _RedactionService_ListRedactionRules_Handler,
+		},
+		{
+			MethodName: "GetRedactionStats",
+			Handler:    _RedactionService_GetRedactionStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/logsentinel.proto",
+}
+
+const (
+	ProcessingService_CreateProcessingRule_FullMethodName  = "/logsentinel.ProcessingService/CreateProcessingRule"
+	ProcessingService_UpdateProcessingRule_FullMethodName  = "/logsentinel.ProcessingService/UpdateProcessingRule"
+	ProcessingService_DeleteProcessingRule_FullMethodName  = "/logsentinel.ProcessingService/DeleteProcessingRule"
+	ProcessingService_ListProcessingRules_FullMethodName   = "/logsentinel.ProcessingService/ListProcessingRules"
+	ProcessingService_DryRunProcessingRules_FullMethodName = "/logsentinel.ProcessingService/DryRunProcessingRules"
+	ProcessingService_SetBodyFormat_FullMethodName         = "/logsentinel.ProcessingService/SetBodyFormat"
+)
+
+// ProcessingServiceClient is the client API for ProcessingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProcessingServiceClient interface {
+	CreateProcessingRule(ctx context.Context, in *SaveProcessingRuleRequest, opts ...grpc.CallOption) (*ProcessingRule, error)
+	UpdateProcessingRule(ctx context.Context, in *SaveProcessingRuleRequest, opts ...grpc.CallOption) (*ProcessingRule, error)
+	DeleteProcessingRule(ctx context.Context, in *DeleteProcessingRuleRequest, opts ...grpc.CallOption) (*DeleteProcessingRuleResponse, error)
+	ListProcessingRules(ctx context.Context, in *ListProcessingRulesRequest, opts ...grpc.CallOption) (*ListProcessingRulesResponse, error)
+	DryRunProcessingRules(ctx context.Context, in *DryRunProcessingRulesRequest, opts ...grpc.CallOption) (*DryRunProcessingRulesResponse, error)
+	SetBodyFormat(ctx context.Context, in *SetBodyFormatRequest, opts ...grpc.CallOption) (*SetBodyFormatResponse, error)
+}
+
+type processingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProcessingServiceClient(cc grpc.ClientConnInterface) ProcessingServiceClient {
+	return &processingServiceClient{cc}
+}
+
+func (c *processingServiceClient) CreateProcessingRule(ctx context.Context, in *SaveProcessingRuleRequest, opts ...grpc.CallOption) (*ProcessingRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessingRule)
+	err := c.cc.Invoke(ctx, ProcessingService_CreateProcessingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processingServiceClient) UpdateProcessingRule(ctx context.Context, in *SaveProcessingRuleRequest, opts ...grpc.CallOption) (*ProcessingRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessingRule)
+	err := c.cc.Invoke(ctx, ProcessingService_UpdateProcessingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processingServiceClient) DeleteProcessingRule(ctx context.Context, in *DeleteProcessingRuleRequest, opts ...grpc.CallOption) (*DeleteProcessingRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteProcessingRuleResponse)
+	err := c.cc.Invoke(ctx, ProcessingService_DeleteProcessingRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processingServiceClient) ListProcessingRules(ctx context.Context, in *ListProcessingRulesRequest, opts ...grpc.CallOption) (*ListProcessingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProcessingRulesResponse)
+	err := c.cc.Invoke(ctx, ProcessingService_ListProcessingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processingServiceClient) DryRunProcessingRules(ctx context.Context, in *DryRunProcessingRulesRequest, opts ...grpc.CallOption) (*DryRunProcessingRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DryRunProcessingRulesResponse)
+	err := c.cc.Invoke(ctx, ProcessingService_DryRunProcessingRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *processingServiceClient) SetBodyFormat(ctx context.Context, in *SetBodyFormatRequest, opts ...grpc.CallOption) (*SetBodyFormatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetBodyFormatResponse)
+	err := c.cc.Invoke(ctx, ProcessingService_SetBodyFormat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProcessingServiceServer is the server API for ProcessingService service.
+// All implementations must embed UnimplementedProcessingServiceServer
+// for forward compatibility.
+type ProcessingServiceServer interface {
+	CreateProcessingRule(context.Context, *SaveProcessingRuleRequest) (*ProcessingRule, error)
+	UpdateProcessingRule(context.Context, *SaveProcessingRuleRequest) (*ProcessingRule, error)
+	DeleteProcessingRule(context.Context, *DeleteProcessingRuleRequest) (*DeleteProcessingRuleResponse, error)
+	ListProcessingRules(context.Context, *ListProcessingRulesRequest) (*ListProcessingRulesResponse, error)
+	DryRunProcessingRules(context.Context, *DryRunProcessingRulesRequest) (*DryRunProcessingRulesResponse, error)
+	SetBodyFormat(context.Context, *SetBodyFormatRequest) (*SetBodyFormatResponse, error)
+	mustEmbedUnimplementedProcessingServiceServer()
+}
+
+// UnimplementedProcessingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProcessingServiceServer struct{}
+
+func (UnimplementedProcessingServiceServer) CreateProcessingRule(context.Context, *SaveProcessingRuleRequest) (*ProcessingRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProcessingRule not implemented")
+}
+func (UnimplementedProcessingServiceServer) UpdateProcessingRule(context.Context, *SaveProcessingRuleRequest) (*ProcessingRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProcessingRule not implemented")
+}
+func (UnimplementedProcessingServiceServer) DeleteProcessingRule(context.Context, *DeleteProcessingRuleRequest) (*DeleteProcessingRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProcessingRule not implemented")
+}
+func (UnimplementedProcessingServiceServer) ListProcessingRules(context.Context, *ListProcessingRulesRequest) (*ListProcessingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProcessingRules not implemented")
+}
+func (UnimplementedProcessingServiceServer) DryRunProcessingRules(context.Context, *DryRunProcessingRulesRequest) (*DryRunProcessingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DryRunProcessingRules not implemented")
+}
+func (UnimplementedProcessingServiceServer) SetBodyFormat(context.Context, *SetBodyFormatRequest) (*SetBodyFormatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBodyFormat not implemented")
+}
+func (UnimplementedProcessingServiceServer) mustEmbedUnimplementedProcessingServiceServer() {}
+func (UnimplementedProcessingServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeProcessingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProcessingServiceServer will
+// result in compilation errors.
+type UnsafeProcessingServiceServer interface {
+	mustEmbedUnimplementedProcessingServiceServer()
+}
+
+func RegisterProcessingServiceServer(s grpc.ServiceRegistrar, srv ProcessingServiceServer) {
+	// If the following call pancis, it indicates UnimplementedProcessingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProcessingService_ServiceDesc, srv)
+}
+
+func _ProcessingService_CreateProcessingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveProcessingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).CreateProcessingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_CreateProcessingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).CreateProcessingRule(ctx, req.(*SaveProcessingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessingService_UpdateProcessingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveProcessingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).UpdateProcessingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_UpdateProcessingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).UpdateProcessingRule(ctx, req.(*SaveProcessingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessingService_DeleteProcessingRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProcessingRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).DeleteProcessingRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_DeleteProcessingRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).DeleteProcessingRule(ctx, req.(*DeleteProcessingRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessingService_ListProcessingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProcessingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).ListProcessingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_ListProcessingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).ListProcessingRules(ctx, req.(*ListProcessingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessingService_DryRunProcessingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunProcessingRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).DryRunProcessingRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_DryRunProcessingRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).DryRunProcessingRules(ctx, req.(*DryRunProcessingRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProcessingService_SetBodyFormat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBodyFormatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProcessingServiceServer).SetBodyFormat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProcessingService_SetBodyFormat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProcessingServiceServer).SetBodyFormat(ctx, req.(*SetBodyFormatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProcessingService_ServiceDesc is the grpc.ServiceDesc for ProcessingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProcessingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logsentinel.ProcessingService",
+	HandlerType: (*ProcessingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateProcessingRule",
+			Handler:    _ProcessingService_CreateProcessingRule_Handler,
+		},
+		{
+			MethodName: "UpdateProcessingRule",
+			Handler:    _ProcessingService_UpdateProcessingRule_Handler,
+		},
+		{
+			MethodName: "DeleteProcessingRule",
+			Handler:    _ProcessingService_DeleteProcessingRule_Handler,
+		},
+		{
+			MethodName: "ListProcessingRules",
+			Handler:    _ProcessingService_ListProcessingRules_Handler,
+		},
+		{
+			MethodName: "DryRunProcessingRules",
+			Handler:    _ProcessingService_DryRunProcessingRules_Handler,
+		},
+		{
+			MethodName: "SetBodyFormat",
+			Handler:    _ProcessingService_SetBodyFormat_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/logsentinel.proto",
@@ -35,4 +35,101 @@ grpcurl -plaintext -d "{}" localhost:50051 logsentinel.LogService/Test
 grpcurl -plaintext localhost:50051 list
 
 # Describe Service
-grpcurl -plaintext localhost:50051 describe logsentinel.UserService
\ No newline at end of file
+grpcurl -plaintext localhost:50051 describe logsentinel.UserService
+
+# List Scheduled Jobs
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{}' localhost:50051 logsentinel.AdminService/ListJobs
+
+# Trigger Job
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{\"name\": \"retention_cleanup\"}' localhost:50051 logsentinel.AdminService/TriggerJob
+
+# Rehydrate Archived Logs
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{\"project_id\": \"project-uuid\", \"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-01-02T00:00:00Z\", \"ttl_hours\": 24}' localhost:50051 logsentinel.AdminService/RehydrateLogs
+
+# List Rehydrations (poll until the rehydration is ready)
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{\"project_id\": \"project-uuid\"}' localhost:50051 logsentinel.AdminService/ListRehydrations
+
+# Export Logs
+grpcurl -plaintext -d '{\"client_id\": \"client-id\", \"project_id\": \"project-uuid\", \"api_key\": \"api-key\", \"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-02-01T00:00:00Z\", \"format\": \"ndjson\", \"compression\": \"gzip\"}' localhost:50051 logsentinel.LogService/ExportLogs
+
+# Create Alert Rule
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"name\": \"error burst\", \"kind\": \"threshold\", \"category\": \"error\", \"threshold\": 50, \"window_seconds\": 300, \"enabled\": true}}' localhost:50051 logsentinel.AlertService/CreateAlertRule
+
+# Create Pattern Alert Rule
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"name\": \"panics\", \"kind\": \"pattern\", \"pattern\": \"panic\", \"window_seconds\": 600, \"enabled\": true}}' localhost:50051 logsentinel.AlertService/CreateAlertRule
+
+# List Alerts
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"state\": \"firing\"}' localhost:50051 logsentinel.AlertService/ListAlerts
+
+# Test Notification Channels
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{\"channel\": \"\", \"message\": \"hello from logsentinel\"}' localhost:50051 logsentinel.AdminService/TestNotification
+
+# Log Stats
+grpcurl -plaintext -d '{\"client_id\": \"client-id\", \"project_id\": \"project-uuid\", \"api_key\": \"api-key\", \"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-01-02T00:00:00Z\", \"bucket_seconds\": 3600}' localhost:50051 logsentinel.LogService/GetLogStats
+
+# Export Usage For Billing
+grpcurl -plaintext -H 'x-admin-token: admin-token' -d '{\"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-02-01T00:00:00Z\"}' localhost:50051 logsentinel.AdminService/ExportUsage
+
+# Top Log Patterns
+grpcurl -plaintext -d '{\"client_id\": \"client-id\", \"project_id\": \"project-uuid\", \"api_key\": \"api-key\", \"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-01-02T00:00:00Z\", \"limit\": 10, \"samples\": 3}' localhost:50051 logsentinel.LogService/GetTopPatterns
+
+# List Anomalies
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"category\": \"error\", \"min_score\": 4}' localhost:50051 logsentinel.AlertService/ListAnomalies
+
+# Send Log With Metadata, sensitive values are redacted before storage
+grpcurl -plaintext -d '{\"client_id\": \"client-id\", \"project_id\": \"project-uuid\", \"api_key\": \"api-key\", \"category\": \"info\", \"message\": \"login by bob@example.com\", \"metadata\": {\"ip\": \"10.0.0.1\"}}' localhost:50051 logsentinel.LogService/SendLog
+
+# Hash Emails Instead Of Masking Them
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"detector\": \"email\", \"action\": \"hash\", \"enabled\": true}}' localhost:50051 logsentinel.RedactionService/SaveRedactionRule
+
+# Drop Logs Matching A Custom Pattern
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"detector\": \"custom\", \"name\": \"ssn\", \"pattern\": \"\\\\b\\\\d{3}-\\\\d{2}-\\\\d{4}\\\\b\", \"action\": \"drop\", \"enabled\": true}}' localhost:50051 logsentinel.RedactionService/SaveRedactionRule
+
+# Redaction Stats
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"from\": \"2025-01-01T00:00:00Z\", \"to\": \"2025-02-01T00:00:00Z\"}' localhost:50051 logsentinel.RedactionService/GetRedactionStats
+
+# Extract Fields From Messages Into Metadata
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"name\": \"http access\", \"action\": \"extract\", \"pattern\": \"%{HTTPMETHOD:method} %{PATH:path} took %{INT:duration_ms}ms\", \"enabled\": true}}' localhost:50051 logsentinel.ProcessingService/CreateProcessingRule
+
+# Keep 10% Of Health Check Logs
+grpcurl -plaintext -d '{\"user_id\": \"user-id\", \"rule\": {\"project_id\": \"project-uuid\", \"name\": \"health checks\", \"match_pattern\": \"GET /healthz\", \"action\": \"sample\", \"sample_percent\": 10, \"enabled\": true}}' localhost:50051 logsentinel.ProcessingService/CreateProcessingRule
+
+# List Processing Rules
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\"}' localhost:50051 logsentinel.ProcessingService/ListProcessingRules
+
+# Dry Run The Stored Rules On A Sample Log
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"category\": \"info\", \"message\": \"GET /api/orders took 35ms\"}' localhost:50051 logsentinel.ProcessingService/DryRunProcessingRules
+
+# Parse JSON And Logfmt Message Bodies
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"body_format\": \"auto\"}' localhost:50051 logsentinel.ProcessingService/SetBodyFormat
+
+# Dry Run Parsing A JSON Body
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"category\": \"info\", \"message\": \"{\\\"level\\\": \\\"error\\\", \\\"msg\\\": \\\"payment failed\\\", \\\"trace_id\\\": \\\"4bf92f3577b34da6\\\", \\\"order_id\\\": 42}\", \"body_format\": \"json\"}' localhost:50051 logsentinel.ProcessingService/DryRunProcessingRules
+
+# Project Categories, logs with other categories are rejected
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\"}' localhost:50051 logsentinel.ProjectService/GetProjectCategories
+
+# Add Custom Categories, an empty list restores the defaults
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"categories\": [\"debug\", \"info\", \"warning\", \"error\", \"audit\", \"billing\"]}' localhost:50051 logsentinel.ProjectService/SetProjectCategories
+
+# Map A Client Certificate Identity To A Project, over mutual TLS its clients need no API key
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\", \"identities\": [\"ingest.example.com\", \"spiffe://example.com/ingest\"]}' localhost:50051 logsentinel.ProjectService/SetClientIdentities
+
+# Project Client Identities
+grpcurl -plaintext -d '{\"project_id\": \"project-uuid\", \"user_id\": \"user-id\"}' localhost:50051 logsentinel.ProjectService/GetClientIdentities
+
+# Send A Log Authenticated By A Client Certificate
+grpcurl -cacert ca.crt -cert client.crt -key client.key -d '{\"project_id\": \"project-uuid\", \"category\": \"info\", \"message\": \"sent over mutual TLS\"}' localhost:50051 logsentinel.LogService/SendLog
+
+# Send A Log In A Client's Trace, the server spans join the trace and its log lines carry the trace ID
+grpcurl -plaintext -H 'traceparent: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01' -d '{\"project_id\": \"project-uuid\", \"api_key\": \"api-key\", \"client_id\": \"client-id\", \"category\": \"info\", \"message\": \"traced log\"}' localhost:50051 logsentinel.LogService/SendLog
+
+# Server Health, an empty service is the overall status
+grpcurl -plaintext -d '{\"service\": \"\"}' localhost:50051 grpc.health.v1.Health/Check
+
+# Health Of The Log Service
+grpcurl -plaintext -d '{\"service\": \"logsentinel.LogService\"}' localhost:50051 grpc.health.v1.Health/Check
+
+# Liveness And Readiness Over HTTP, readyz also shows the alert and notification queue backlog
+curl -i localhost:8080/healthz
+curl -i localhost:8080/readyz